@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/boltdb/bolt"
+
+	"github.com/chammaaomar/golang-tdd/quiz"
+)
+
+// boardsOf returns the boards of decks that are of the question bank at
+// path, whichever of its questions were asked, or else the one named path
+func boardsOf(decks []string, path string) []string {
+	bank, err := filepath.Abs(path)
+	if err != nil {
+		bank = path
+	}
+	var boards []string
+	for _, deck := range decks {
+		if deck == path || deck == bank || strings.HasPrefix(deck, bank+" -") {
+			boards = append(boards, deck)
+		}
+	}
+	return boards
+}
+
+// leaderboardCommand prints the top scores of every deck, or of the decks
+// of a single question bank if one is given
+func leaderboardCommand(args []string) {
+	flags := flag.NewFlagSet("leaderboard", flag.ExitOnError)
+	dbPath := flags.String("leaderboard", "leaderboard.db", "path to the leaderboard database")
+	deck := flags.String("deck", "", "only show the scores of this question bank, e.g. problems.csv, however it was filtered")
+	top := flags.Int("n", 10, "number of scores to show per deck")
+	flags.Parse(args)
+
+	db, err := bolt.Open(*dbPath, 0600, &bolt.Options{Timeout: time.Second, ReadOnly: true})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	leaderboard := quiz.NewLeaderboard(db)
+
+	decks, err := leaderboard.Decks()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(*deck) > 0 {
+		decks = boardsOf(decks, *deck)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	for _, deck := range decks {
+		entries, err := leaderboard.Top(deck, *top)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintf(w, "%s\n", deck)
+		for i, entry := range entries {
+			fmt.Fprintf(w, "%d.\t%s\t%d/%d\t%s\t%s\n", i+1, entry.Player, entry.Score, entry.MaxScore,
+				entry.Duration.Round(time.Second), entry.Time.Format("2006-01-02 15:04"))
+		}
+		fmt.Fprintln(w)
+	}
+	w.Flush()
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/chammaaomar/golang-tdd/quiz"
+)
+
+// lintCommand reports every problem in the question banks given,
+// problems.csv if there are none, and exits with a non-zero status if
+// there are any, for use in CI
+func lintCommand(args []string) {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	header := flags.Bool("header", false, "whether the CSV question banks have a header")
+	flags.Parse(args)
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"problems.csv"}
+	}
+	found := 0
+	for _, path := range paths {
+		issues, err := quiz.LintFile(path, *header)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, issue := range issues {
+			fmt.Println(issue)
+		}
+		found += len(issues)
+	}
+	if found > 0 {
+		os.Exit(1)
+	}
+}
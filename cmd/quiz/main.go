@@ -1,16 +1,315 @@
 package main
 
 import (
+	"bytes"
+	"errors"
 	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/boltdb/bolt"
 
 	"github.com/chammaaomar/golang-tdd/quiz"
 )
 
 var timerPtr = flag.Int("timer", 30, "time limit in seconds")
-var csvPathPtr = flag.String("questions", "problems.csv", "path to CSV with question/answer pairs")
+var countdownPtr = flag.Bool("countdown", false, "show the time left before every question")
+var warningsPtr = flag.String("warnings", "", "comma-separated seconds left at which to warn that time is running out, e.g. 10,5")
+var questionTimerPtr = flag.Int("question-timer", 0, "time limit of every question in seconds, 0 for none")
+var csvPathPtr = flag.String("questions", "problems.csv", "path to the question bank: a CSV, or JSON/YAML if it ends in .json/.yaml/.yml")
 var headerPtr = flag.Bool("header", false, "whether the questions CSV has a header")
+var shufflePtr = flag.Bool("shuffle", false, "ask the questions in a random order")
+var practicePtr = flag.Bool("practice", false, "show the answer to every question missed, and ask it again later")
+var retriesPtr = flag.Int("retries", 2, "how many more times a missed question is asked in practice mode")
+var penaltyPtr = flag.Float64("penalty", 0, "points taken off for every wrong answer")
+var streakBonusPtr = flag.Float64("streak-bonus", 0, "extra fraction of points for every correct answer in a row")
+var speedBonusPtr = flag.Float64("speed-bonus", 0, "extra fraction of points for an instant answer, shrinking to nothing at -speed-limit")
+var speedLimitPtr = flag.Int("speed-limit", 10, "seconds after which answers earn no speed bonus")
+var shuffleOptionsPtr = flag.Bool("shuffle-options", false, "shuffle the options of multiple-choice questions")
+var categoryPtr = flag.String("category", "", "only ask the questions of this category")
+var tagsPtr = flag.String("tags", "", "only ask the questions with any of these comma-separated tags")
+var countPtr = flag.Int("n", 0, "ask only a random sample of n questions, 0 for all of them")
+var adaptivePtr = flag.Bool("adaptive", false, "pick harder questions after correct answers and easier ones after misses, and estimate your skill")
+var seedPtr = flag.Int64("seed", 0, "seed for shuffling and sampling, to replay a session. 0 picks one from the clock")
+var reportPtr = flag.String("report", "", "path to write a report of the game to")
+var reportFormatPtr = flag.String("report-format", "", "format of the report: json, csv or junit. Guessed from the report's extension if empty")
+var namePtr = flag.String("name", os.Getenv("USER"), "player name for the leaderboard")
+var langPtr = flag.String("lang", "en", "language of the quiz: en, fr or es")
+var messagesPtr = flag.String("messages", "", "path to a JSON or YAML file of messages, taking precedence over -lang")
+var resumePtr = flag.String("resume", "", "file to save the game to if you quit or interrupt it, and to resume it from next time")
+var leaderboardPtr = flag.String("leaderboard", "", "path to the leaderboard database to record the game in, e.g. leaderboard.db")
+var generatePtr = flag.Bool("generate", false, "make up arithmetic problems instead of reading a question bank")
+var operatorsPtr = flag.String("operators", "+-*/", "operators of the generated problems")
+var minPtr = flag.Int("min", 0, "smallest operand of the generated problems")
+var maxPtr = flag.Int("max", 10, "largest operand of the generated problems")
+var difficultyPtr = flag.Int("difficulty", 1, "number of operations in every generated problem")
 
 func main() {
+	flag.Usage = usage
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "leaderboard":
+			leaderboardCommand(os.Args[2:])
+			return
+		case "serve":
+			serveCommand(os.Args[2:])
+			return
+		case "rooms":
+			roomsCommand(os.Args[2:])
+			return
+		case "verify":
+			verifyCommand(os.Args[2:])
+			return
+		case "lint":
+			lintCommand(os.Args[2:])
+			return
+		case "study":
+			studyCommand(os.Args[2:])
+			return
+		}
+	}
 	flag.Parse()
-	quiz.PlayGame(*csvPathPtr, *timerPtr, *headerPtr)
+	var snapshot *quiz.Snapshot
+	if len(*resumePtr) > 0 {
+		snapshot = loadSnapshot(*resumePtr)
+	}
+	if snapshot != nil && *seedPtr == 0 {
+		// generated problems are made up again from the same seed
+		*seedPtr = snapshot.Seed
+	}
+	format := reportFormat()
+	source := questionSource()
+	config := gameConfig()
+	config.Resume = snapshot
+	result, err := quiz.PlayGame(source, config)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(*reportPtr) > 0 {
+		writeReport(*reportPtr, format, result)
+	}
+	if len(*resumePtr) > 0 {
+		saveSnapshot(*resumePtr, result.Snapshot)
+		if result.Snapshot != nil {
+			// the game goes on the leaderboard once it's finished
+			return
+		}
+	}
+	if len(*leaderboardPtr) > 0 {
+		entry := quiz.NewEntry(*namePtr, boardName(), result, time.Now())
+		recordEntry(*leaderboardPtr, entry)
+	}
+}
+
+// questionSource returns the question bank named by the flags, or a
+// generator of arithmetic problems with -generate. Generated problems are
+// seeded with -seed, picking one from the clock if it's zero, and the
+// seed is printed so that the session can be replayed
+func questionSource() quiz.QuestionSource {
+	if !*generatePtr {
+		return quiz.File(*csvPathPtr, *headerPtr)
+	}
+	if *seedPtr == 0 {
+		*seedPtr = time.Now().UnixNano()
+	}
+	fmt.Printf("Generating problems with -seed %d\n", *seedPtr)
+	return quiz.Generator{
+		Count:      *countPtr,
+		Operators:  *operatorsPtr,
+		Min:        *minPtr,
+		Max:        *maxPtr,
+		Difficulty: *difficultyPtr,
+		Seed:       *seedPtr,
+	}
+}
+
+// deckName is the name of the deck on the leaderboard
+func deckName() string {
+	if *generatePtr {
+		return fmt.Sprintf("generated %s %d..%d x%d", *operatorsPtr, *minPtr, *maxPtr, *difficultyPtr)
+	}
+	return filepath.Base(*csvPathPtr)
+}
+
+// boardName is the name of the deck on the leaderboard: the question bank
+// by its absolute path, so that banks with the same file name don't share
+// a board, followed by the flags picking which of its questions are asked
+func boardName() string {
+	name := deckName()
+	if !*generatePtr {
+		if path, err := filepath.Abs(*csvPathPtr); err == nil {
+			name = path
+		}
+	}
+	if len(*categoryPtr) > 0 {
+		name += " -category " + *categoryPtr
+	}
+	if tags := tags(); len(tags) > 0 {
+		sort.Strings(tags)
+		name += " -tags " + strings.Join(tags, ",")
+	}
+	if *countPtr > 0 {
+		name += fmt.Sprintf(" -n %d", *countPtr)
+	}
+	return name
+}
+
+// gameConfig gathers the options of the game from the flags
+func gameConfig() quiz.Config {
+	return quiz.Config{
+		Timer:          *timerPtr,
+		QuestionTimer:  *questionTimerPtr,
+		Countdown:      *countdownPtr,
+		Warnings:       warnings(),
+		Shuffle:        *shufflePtr,
+		ShuffleOptions: *shuffleOptionsPtr,
+		Practice:       *practicePtr,
+		Retries:        *retriesPtr,
+		Category:       *categoryPtr,
+		Tags:           tags(),
+		Count:          *countPtr,
+		Adaptive:       *adaptivePtr,
+		Messages:       messages(),
+		Seed:           *seedPtr,
+		Scorer: quiz.WeightedScorer{
+			Penalty:     *penaltyPtr,
+			StreakBonus: *streakBonusPtr,
+			SpeedBonus:  *speedBonusPtr,
+			SpeedLimit:  time.Duration(*speedLimitPtr) * time.Second,
+		},
+	}
+}
+
+// messages loads the messages of the quiz from -messages, or else picks
+// the ones of -lang
+func messages() quiz.Messages {
+	var messages quiz.Messages
+	var err error
+	if len(*messagesPtr) > 0 {
+		messages, err = quiz.LoadMessages(*messagesPtr)
+	} else {
+		messages, err = quiz.Catalog(*langPtr)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	return messages
+}
+
+// warnings parses the -warnings flag
+func warnings() []int {
+	if len(*warningsPtr) == 0 {
+		return nil
+	}
+	var seconds []int
+	for _, field := range strings.Split(*warningsPtr, ",") {
+		warning, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			log.Fatalf("-warnings: %v", err)
+		}
+		seconds = append(seconds, warning)
+	}
+	return seconds
+}
+
+// tags splits the -tags flag
+func tags() []string {
+	var tags []string
+	for _, tag := range strings.Split(*tagsPtr, ",") {
+		if tag = strings.TrimSpace(tag); len(tag) > 0 {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage:\n  %s [flags]              play the quiz\n", os.Args[0])
+	fmt.Fprintf(out, "  %s study [flags]        review the questions due, spaced out over days\n", os.Args[0])
+	fmt.Fprintf(out, "  %s serve [flags]        play the quiz in the browser\n", os.Args[0])
+	fmt.Fprintf(out, "  %s rooms [flags]        host multiplayer rooms over TCP\n", os.Args[0])
+	fmt.Fprintf(out, "  %s leaderboard [flags]  show the top scores of every deck\n", os.Args[0])
+	fmt.Fprintf(out, "  %s verify [files]       check the answers of arithmetic questions\n", os.Args[0])
+	fmt.Fprintf(out, "  %s lint [files]         report every problem in question banks\n\nFlags:\n", os.Args[0])
+	flag.PrintDefaults()
+}
+
+// recordEntry adds entry to the leaderboard database at path
+func recordEntry(path string, entry quiz.Entry) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	err = quiz.NewLeaderboard(db).Record(entry)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// loadSnapshot reads the game saved at path, if there's one
+func loadSnapshot(path string) *quiz.Snapshot {
+	snapshot, err := quiz.LoadSnapshot(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Resuming the game saved in %s\n", path)
+	return snapshot
+}
+
+// saveSnapshot saves the game to path if the player quit before the end,
+// or else removes the game saved there, which is over
+func saveSnapshot(path string, snapshot *quiz.Snapshot) {
+	if snapshot == nil {
+		err := os.Remove(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Fatal(err)
+		}
+		return
+	}
+	err := snapshot.Save(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Game saved, resume it with -resume %s\n", path)
+}
+
+// reportFormat is the format of the report, from -report-format or else
+// the extension of -report. It's checked before the game so that a typo
+// doesn't cost the report of a whole game
+func reportFormat() string {
+	format := *reportFormatPtr
+	if len(format) == 0 {
+		format = quiz.ReportFormat(*reportPtr)
+	}
+	if err := quiz.CheckReportFormat(format); len(*reportPtr) > 0 && err != nil {
+		log.Fatalf("-report-format: %v", err)
+	}
+	return format
+}
+
+// writeReport writes the result of the game to path in format. The file
+// is only written once the whole report is, so that a failed report
+// doesn't wipe the one before it
+func writeReport(path, format string, result quiz.Result) {
+	var report bytes.Buffer
+	err := quiz.WriteReport(&report, result, format)
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = ioutil.WriteFile(path, report.Bytes(), 0666)
+	if err != nil {
+		log.Fatal(err)
+	}
 }
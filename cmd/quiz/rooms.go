@@ -0,0 +1,27 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/chammaaomar/golang-tdd/quiz"
+)
+
+// roomsCommand runs a lobby of multiplayer rooms over TCP. It takes the
+// same flags as the game, plus the address to listen on
+func roomsCommand(args []string) {
+	addr := flag.String("addr", "localhost:4000", "address to listen for players on")
+	flag.CommandLine.Parse(args)
+	lobby, err := quiz.NewLobby(questionSource(), gameConfig())
+	if err != nil {
+		log.Fatal(err)
+	}
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Waiting for players on %s, connect with nc or telnet\n", listener.Addr())
+	log.Fatal(lobby.Serve(listener))
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/chammaaomar/golang-tdd/quiz"
+)
+
+// serveCommand serves the quiz over HTTP. It takes the same flags as the
+// game, plus the address to listen on
+func serveCommand(args []string) {
+	addr := flag.String("addr", "localhost:8080", "address to serve the quiz on")
+	flag.CommandLine.Parse(args)
+	server, err := quiz.NewServer(questionSource(), gameConfig())
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Starting the server on %s\n", *addr)
+	log.Fatal(http.ListenAndServe(*addr, server))
+}
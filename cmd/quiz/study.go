@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/boltdb/bolt"
+
+	"github.com/chammaaomar/golang-tdd/quiz"
+)
+
+// studyCommand asks the questions due for review, and then prints how many
+// are coming up in the next days. It takes the same flags as the game,
+// plus where to keep the study history
+func studyCommand(args []string) {
+	dbPath := flag.String("study", "study.db", "path to the study history database")
+	days := flag.Int("days", 7, "number of days of upcoming reviews to show")
+	flag.CommandLine.Parse(args)
+
+	db, err := bolt.Open(*dbPath, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	store := quiz.NewStudyStore(db)
+	source := questionSource()
+	name := deckName()
+	_, err = quiz.Study(source, name, store, gameConfig())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	deck, err := source.Questions()
+	if err != nil {
+		log.Fatal(err)
+	}
+	cards, err := store.Cards(name)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Upcoming reviews:")
+	for day, due := range cards.Forecast(deck, time.Now(), *days) {
+		switch day {
+		case 0:
+			fmt.Printf("  today     %d\n", due)
+		case 1:
+			fmt.Printf("  tomorrow  %d\n", due)
+		default:
+			fmt.Printf("  in %d days %d\n", day, due)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/chammaaomar/golang-tdd/quiz"
+)
+
+// verifyCommand checks the answers of the arithmetic questions of every
+// question bank given, problems.csv if there are none, and exits with a
+// non-zero status if any of them is wrong
+func verifyCommand(args []string) {
+	flags := flag.NewFlagSet("verify", flag.ExitOnError)
+	header := flags.Bool("header", false, "whether the CSV question banks have a header")
+	flags.Parse(args)
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"problems.csv"}
+	}
+	wrong := 0
+	for _, path := range paths {
+		deck, err := quiz.File(path, *header).Questions()
+		if err != nil {
+			log.Fatalf("%s: %v", path, err)
+		}
+		checked, mismatches := deck.Verify()
+		for _, mismatch := range mismatches {
+			where := fmt.Sprintf("question %d", mismatch.Index+1)
+			if mismatch.Question.Line > 0 {
+				where = fmt.Sprintf("line %d", mismatch.Question.Line)
+			}
+			fmt.Printf("%s:%s: %s is %s, not %s\n", path, where, mismatch.Question.Text,
+				mismatch.Expected, mismatch.Question.Answer)
+		}
+		fmt.Printf("%s: checked %d arithmetic questions, %d wrong\n", path, checked, len(mismatches))
+		wrong += len(mismatches)
+	}
+	if wrong > 0 {
+		os.Exit(1)
+	}
+}
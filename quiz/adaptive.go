@@ -0,0 +1,81 @@
+package quiz
+
+import (
+	"math"
+	"sort"
+)
+
+// startAdaptive sets s up to pick its questions from deck as it goes,
+// starting at the median difficulty. s.mu must be held, or s not shared yet
+func (s *session) startAdaptive(deck Deck) {
+	s.pool, s.deck = deck, nil
+	if s.config.Count > 0 && s.config.Count < len(deck) {
+		s.questions = s.config.Count
+	}
+	if len(deck) == 0 {
+		return
+	}
+	levels := make([]int, 0, len(deck))
+	for _, question := range deck {
+		levels = append(levels, question.Difficulty)
+	}
+	sort.Ints(levels)
+	s.minLevel, s.maxLevel = levels[0], levels[len(levels)-1]
+	s.level = levels[len(levels)/2]
+	s.skill = float64(s.level)
+}
+
+// pick moves the question of the pool closest to the current level to the
+// end of the deck, and returns false if no more questions are to be asked.
+// s.mu must be held
+func (s *session) pick() bool {
+	if len(s.pool) == 0 || s.picked >= s.questions {
+		return false
+	}
+	best := 0
+	for i, question := range s.pool {
+		if distance(question.Difficulty, s.level) < distance(s.pool[best].Difficulty, s.level) {
+			best = i
+		}
+	}
+	s.deck = append(s.deck, s.pool[best])
+	s.pool = append(s.pool[:best], s.pool[best+1:]...)
+	s.picked++
+	return true
+}
+
+// adapt raises the level after a correct answer and lowers it after a
+// miss, and updates the skill rating like an Elo rating against the
+// difficulty of the question. s.mu must be held
+func (s *session) adapt(answer Answer) {
+	outcome := 0.0
+	if answer.Correct {
+		outcome = 1
+		if s.level < s.maxLevel {
+			s.level++
+		}
+	} else if s.level > s.minLevel {
+		s.level--
+	}
+	expected := 1 / (1 + math.Exp(float64(answer.Question.Difficulty)-s.skill))
+	s.skill += outcome - expected
+}
+
+// picksLeft is the number of questions still to be picked. s.mu must be
+// held
+func (s *session) picksLeft() int {
+	if !s.config.Adaptive {
+		return 0
+	}
+	if left := s.questions - s.picked; left < len(s.pool) {
+		return left
+	}
+	return len(s.pool)
+}
+
+func distance(a, b int) int {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
@@ -0,0 +1,70 @@
+package quiz
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAdaptive(t *testing.T) {
+	deck := Deck{
+		{Text: "1+1", Answer: "2", Difficulty: 1},
+		{Text: "12+9", Answer: "21", Difficulty: 2},
+		{Text: "17*3", Answer: "51", Difficulty: 3},
+		{Text: "144/12", Answer: "12", Difficulty: 4},
+		{Text: "37*24", Answer: "888", Difficulty: 5},
+	}
+	noTimeout := &expiringSleeper{}
+	asked := func(result Result) []string {
+		texts := make([]string, 0, len(result.Answers))
+		for _, answer := range result.Answers {
+			texts = append(texts, answer.Question.Text)
+		}
+		return texts
+	}
+
+	t.Run("Adaptive mode should ask harder questions after correct answers", func(t *testing.T) {
+		config := Config{Timer: 30, Adaptive: true, Count: 3}
+		result, _ := playGame(deck, config, strings.NewReader("\n51\n12\n888\n"), noTimeout, &spyPrinter{})
+		expected := []string{"17*3", "144/12", "37*24"}
+		if !reflect.DeepEqual(asked(result), expected) {
+			t.Fatalf("Expected questions %v, got %v", expected, asked(result))
+		}
+		if result.MaxScore != 3 || result.Score != 3 || result.Skill <= 3 {
+			t.Fatalf("Expected 3 out of 3 and a skill above 3, got %+v", result)
+		}
+	})
+
+	t.Run("Adaptive mode should ask easier questions after misses", func(t *testing.T) {
+		config := Config{Timer: 30, Adaptive: true}
+		result, _ := playGame(deck, config, strings.NewReader("\n0\n0\n2\n12\n0\n"), noTimeout, &spyPrinter{})
+		expected := []string{"17*3", "12+9", "1+1", "144/12", "37*24"}
+		if !reflect.DeepEqual(asked(result), expected) {
+			t.Fatalf("Expected questions %v, got %v", expected, asked(result))
+		}
+		if result.Score != 2 || result.Skill >= 3 {
+			t.Fatalf("Expected 2 correct and a skill below 3, got %+v", result)
+		}
+	})
+
+	t.Run("Adaptive mode should print the estimated skill", func(t *testing.T) {
+		printingSpy := &recordingPrinter{}
+		config := Config{Timer: 30, Adaptive: true, Count: 1}
+		result, _ := playGame(deck, config, strings.NewReader("\n51\n"), noTimeout, printingSpy)
+		last := printingSpy.lines[len(printingSpy.lines)-1]
+		if last != "Estimated skill: 3.5" {
+			t.Fatalf("Expected the skill 3.5 to be printed last, got %q in %+v", last, result)
+		}
+	})
+
+	t.Run("CSV should read the difficulty of questions", func(t *testing.T) {
+		questions, err := CSVSource{Reader: strings.NewReader("1+1,2,difficulty=3\n")}.Questions()
+		if err != nil || questions[0].Difficulty != 3 {
+			t.Fatalf("Expected difficulty 3, got %+v and %v", questions, err)
+		}
+		_, err = CSVSource{Reader: strings.NewReader("1+1,2,difficulty=hard\n")}.Questions()
+		if err == nil {
+			t.Fatalf("Expected an error for a difficulty that isn't a number")
+		}
+	})
+}
@@ -0,0 +1,191 @@
+package quiz
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+var errMissingField = errors.New("question bank has an entry without a question or an answer")
+
+// entry is how a question is written in JSON and YAML question banks,
+// see YAMLSource for the format
+type entry struct {
+	Question   string            `json:"question" yaml:"question"`
+	Answer     text              `json:"answer" yaml:"answer"`
+	Accept     []text            `json:"accept" yaml:"accept"`
+	Match      string            `json:"match" yaml:"match"`
+	Options    []text            `json:"options" yaml:"options"`
+	Category   string            `json:"category" yaml:"category"`
+	Tags       []string          `json:"tags" yaml:"tags"`
+	Points     int               `json:"points" yaml:"points"`
+	Hint       string            `json:"hint" yaml:"hint"`
+	Timer      int               `json:"timer" yaml:"timer"`
+	Difficulty int               `json:"difficulty" yaml:"difficulty"`
+	Meta       map[string]string `json:"meta" yaml:"meta"`
+}
+
+// text is a string that can also be written as a bare number in JSON, so
+// that "answer": 12 works as well as "answer": "12"
+type text string
+
+func (t *text) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(data, []byte(`"`)) {
+		var s string
+		err := json.Unmarshal(data, &s)
+		*t = text(s)
+		return err
+	}
+	var n json.Number
+	err := json.Unmarshal(data, &n)
+	*t = text(n)
+	return err
+}
+
+func toStrings(texts []text) []string {
+	if texts == nil {
+		return nil
+	}
+	strs := make([]string, len(texts))
+	for i, t := range texts {
+		strs[i] = string(t)
+	}
+	return strs
+}
+
+// toQuestion validates an entry of a question bank and turns it into a
+// Question. A blank answer to an arithmetic question is computed
+func toQuestion(e entry) (Question, error) {
+	question := Question{
+		Text:       e.Question,
+		Answer:     strings.TrimSpace(string(e.Answer)),
+		Accept:     toStrings(e.Accept),
+		Match:      e.Match,
+		Options:    toStrings(e.Options),
+		Category:   e.Category,
+		Tags:       e.Tags,
+		Points:     e.Points,
+		Hint:       e.Hint,
+		Timer:      e.Timer,
+		Difficulty: e.Difficulty,
+		Meta:       e.Meta,
+	}
+	computeAnswer(&question)
+	if question.Text == "" || question.Answer == "" {
+		return question, errMissingField
+	}
+	return question, question.validate()
+}
+
+// toDeck turns the entries of a question bank into a Deck, in order.
+// Errors are ParseErrors whose Line is the number of the offending entry
+func toDeck(entries []entry) (Deck, error) {
+	deck := make(Deck, 0, len(entries))
+	for i, e := range entries {
+		question, err := toQuestion(e)
+		if err != nil {
+			return deck, &ParseError{Line: i + 1, Err: err}
+		}
+		deck = append(deck, question)
+	}
+	return deck, nil
+}
+
+// JSONSource reads a question bank in JSON format from Reader. The bank
+// is an array of entries with the same fields as the YAML format, see
+// YAMLSource
+type JSONSource struct {
+	Reader io.Reader
+}
+
+// Questions decodes the whole bank into a Deck
+func (j JSONSource) Questions() (Deck, error) {
+	entries, err := j.entries()
+	if err != nil {
+		return nil, err
+	}
+	return toDeck(entries)
+}
+
+func (j JSONSource) entries() ([]entry, error) {
+	entries := make([]entry, 0, 10)
+	decoder := json.NewDecoder(j.Reader)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&entries)
+	return entries, err
+}
+
+// YAMLSource reads a question bank in YAML format from Reader. The bank
+// is a list of entries in the format
+//
+//   - question: What is the capital of France?
+//     answer: Paris
+//     accept: [Paname]
+//     category: geography
+//     tags: [europe, capitals]
+//     points: 2
+//     hint: It's also called the city of light
+//     timer: 10
+//
+// where only question and answer are required, and answer may be left
+// out of arithmetic questions to have it computed. accept lists other
+// answers that are also correct, timer is how many seconds the player has
+// to answer, and match, options and meta work like the key=value columns
+// of a CSV
+type YAMLSource struct {
+	Reader io.Reader
+}
+
+// Questions decodes the whole bank into a Deck
+func (y YAMLSource) Questions() (Deck, error) {
+	entries, err := y.entries()
+	if err != nil {
+		return nil, err
+	}
+	return toDeck(entries)
+}
+
+func (y YAMLSource) entries() ([]entry, error) {
+	yml, err := ioutil.ReadAll(y.Reader)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]entry, 0, 10)
+	err = yaml.UnmarshalStrict(yml, &entries)
+	return entries, err
+}
+
+// sourceFor picks the QuestionSource for the file name from its extension:
+// .json for JSON, .yaml or .yml for YAML, and CSV for anything else
+func sourceFor(name string, reader io.Reader, header bool) QuestionSource {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		return JSONSource{Reader: reader}
+	case ".yaml", ".yml":
+		return YAMLSource{Reader: reader}
+	default:
+		return CSVSource{Reader: reader, Header: header}
+	}
+}
+
+// File returns a QuestionSource reading the question bank at path, in
+// the format given by its extension: .json for JSON, .yaml or .yml for
+// YAML, and CSV for anything else. header is only used for CSV files
+func File(path string, header bool) QuestionSource {
+	return SourceFunc(func() (Deck, error) {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		deck, err := sourceFor(path, file, header).Questions()
+		return deck, inFile(err, path)
+	})
+}
@@ -0,0 +1,96 @@
+package quiz
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+var errBadOptions = errors.New("multiple-choice question needs between 2 and 26 options, one of which is the answer")
+
+// maxOptions is how many options can be lettered a to z
+const maxOptions = 26
+
+// optionLabel returns the label of the i-th option, e.g. "b)" for 1
+func optionLabel(i int) string {
+	return fmt.Sprintf("%c)", 'a'+i)
+}
+
+// isMultipleChoice reports whether q is answered by picking one of its
+// options rather than typing the answer
+func (q Question) isMultipleChoice() bool {
+	return len(q.Options) > 0
+}
+
+// validateOptions checks that a multiple-choice question has a sensible
+// number of options and that the answer is one of them
+func (q Question) validateOptions() error {
+	if !q.isMultipleChoice() {
+		return nil
+	}
+	if len(q.Options) < 2 || len(q.Options) > maxOptions {
+		return errBadOptions
+	}
+	for _, option := range q.Options {
+		if q.Check(option) {
+			return nil
+		}
+	}
+	return errBadOptions
+}
+
+// choose returns the option the player picked by typing its letter, and
+// false if input isn't the letter of any option
+func (q Question) choose(input string) (string, bool) {
+	letter := strings.ToLower(strings.TrimSpace(input))
+	if len(letter) != 1 {
+		return "", false
+	}
+	i := int(letter[0]) - 'a'
+	if i < 0 || i >= len(q.Options) {
+		return "", false
+	}
+	return q.Options[i], true
+}
+
+// quits reports whether input is the quit keyword rather than the letter
+// of one of q's options, e.g. "q" when q has 17 options or more
+func (q Question) quits(input, quit string) bool {
+	if input != quit {
+		return false
+	}
+	_, picked := q.choose(input)
+	return !picked
+}
+
+// solution is the answer to q as the player would give it: with the
+// letter of its option for multiple-choice questions
+func (q Question) solution() string {
+	for i, option := range q.Options {
+		if q.Check(option) {
+			return optionLabel(i) + " " + option
+		}
+	}
+	return q.Answer
+}
+
+// ShuffleOptions returns a copy of the deck where the options of every
+// multiple-choice question are shuffled using rng. The order of the
+// questions themselves is untouched
+func (d Deck) ShuffleOptions(rng *rand.Rand) Deck {
+	shuffled := make(Deck, len(d))
+	copy(shuffled, d)
+	for i, q := range shuffled {
+		if !q.isMultipleChoice() {
+			continue
+		}
+		options := make([]string, len(q.Options))
+		copy(options, q.Options)
+		rng.Shuffle(len(options), func(i, j int) {
+			options[i], options[j] = options[j], options[i]
+		})
+		shuffled[i].Options = options
+	}
+	return shuffled
+}
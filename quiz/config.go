@@ -0,0 +1,168 @@
+package quiz
+
+import (
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// Config holds the options of a game. The zero value asks every question
+// of the deck in order, with no time limit
+type Config struct {
+	// Timer is the time limit of the whole game, in seconds, or none if
+	// it's zero
+	Timer int
+	// QuestionTimer, if positive, is the time limit of every question, in
+	// seconds. Questions with their own Timer use that instead
+	QuestionTimer int
+	// Shuffle asks the questions in a random order
+	Shuffle bool
+	// Practice shows the answer to every question missed, and asks it
+	// again at the end of the game, up to Retries more times
+	Practice bool
+	Retries  int
+	// ShuffleOptions shows the options of multiple-choice questions in a
+	// random order
+	ShuffleOptions bool
+	// Category, if set, only asks the questions of that category
+	Category string
+	// Tags, if set, only asks the questions with at least one of the tags
+	Tags []string
+	// Countdown shows the time left before every question
+	Countdown bool
+	// Warnings are the times left, in seconds, at which the player is
+	// warned that time is running out, e.g. 10 and 5
+	Warnings []int
+	// Count, if positive, asks only a random sample of Count questions
+	Count int
+	// Adaptive picks every question by its Difficulty as the game goes:
+	// harder after a correct answer, easier after a miss. Count, if
+	// positive, caps how many questions are picked rather than sampling
+	// them, and the result estimates the player's skill
+	Adaptive bool
+	// Messages is what the game says to the player. Messages left empty,
+	// e.g. all of them in the zero value, are the English ones
+	Messages Messages
+	// Scorer weighs the answers into points, on top of the count of
+	// correct answers. When it's nil a zero WeightedScorer is used
+	Scorer Scorer
+	// Seed seeds every random choice of the game, so that the same seed
+	// and deck replay the same session. When it's zero a seed is picked
+	// from the clock, and reported at the end of the game
+	Seed int64
+	// Resume, if set, carries on with a game saved when the player quit,
+	// see Result.Snapshot, instead of starting a new one. The deck must be
+	// the one the game was played with
+	Resume *Snapshot
+}
+
+// random reports whether the game makes any random choices, i.e. whether
+// its seed matters
+func (c Config) random() bool {
+	return c.Shuffle || c.ShuffleOptions || (c.Count > 0 && !c.Adaptive)
+}
+
+// scorer returns the Scorer of the game
+func (c Config) scorer() Scorer {
+	if c.Scorer == nil {
+		return WeightedScorer{}
+	}
+	return c.Scorer
+}
+
+// messages returns the catalog of messages of the game
+func (c Config) messages() Messages {
+	return c.Messages.withDefaults()
+}
+
+// questionTimer returns how long the player has to answer q, or zero if
+// there's no limit
+func (c Config) questionTimer(q Question) time.Duration {
+	if q.Timer > 0 {
+		return time.Duration(q.Timer) * time.Second
+	}
+	return time.Duration(c.QuestionTimer) * time.Second
+}
+
+// arrange applies the filters, shuffling and sampling of c to deck, and
+// returns the seed it used. Without a seed, the time on watch is the seed
+func (c Config) arrange(deck Deck, watch clock) (Deck, int64) {
+	seed := c.Seed
+	if seed == 0 {
+		seed = watch.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed))
+	if len(c.Category) > 0 || len(c.Tags) > 0 {
+		deck = deck.Filter(c.Category, c.Tags)
+	}
+	if c.Count > 0 && !c.Adaptive {
+		deck = deck.Sample(c.Count, rng)
+	}
+	if c.Shuffle {
+		deck = deck.Shuffle(rng)
+	}
+	if c.ShuffleOptions {
+		deck = deck.ShuffleOptions(rng)
+	}
+	return deck, seed
+}
+
+// Filter returns the questions of the deck in category, if it's not empty,
+// and with at least one of tags, if there are any, in deck order. Both are
+// compared ignoring case
+func (d Deck) Filter(category string, tags []string) Deck {
+	filtered := make(Deck, 0, len(d))
+	for _, question := range d {
+		if len(category) > 0 && !strings.EqualFold(question.Category, category) {
+			continue
+		}
+		if len(tags) > 0 && !question.hasTag(tags) {
+			continue
+		}
+		filtered = append(filtered, question)
+	}
+	return filtered
+}
+
+// hasTag reports whether q has any of tags
+func (q Question) hasTag(tags []string) bool {
+	for _, tag := range q.Tags {
+		for _, wanted := range tags {
+			if strings.EqualFold(tag, wanted) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Shuffle returns a copy of the deck with its questions in a random
+// order drawn from rng
+func (d Deck) Shuffle(rng *rand.Rand) Deck {
+	shuffled := make(Deck, len(d))
+	copy(shuffled, d)
+	rng.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	return shuffled
+}
+
+// Sample returns n questions of the deck drawn at random from rng, kept
+// in deck order. If the deck has n questions or fewer, all of them are
+// returned
+func (d Deck) Sample(n int, rng *rand.Rand) Deck {
+	if n >= len(d) {
+		return d
+	}
+	picked := make([]bool, len(d))
+	for _, i := range rng.Perm(len(d))[:n] {
+		picked[i] = true
+	}
+	sample := make(Deck, 0, n)
+	for i, question := range d {
+		if picked[i] {
+			sample = append(sample, question)
+		}
+	}
+	return sample
+}
@@ -0,0 +1,75 @@
+package quiz
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	return f.now
+}
+
+func TestCountdown(t *testing.T) {
+	deck := Deck{
+		{Text: "1+4", Answer: "5"},
+		{Text: "Capital of France?", Answer: "Paris"},
+	}
+
+	t.Run("The countdown should show the time left before every question", func(t *testing.T) {
+		printingSpy := &recordingPrinter{}
+		config := Config{Timer: 30, Countdown: true}
+		watch := &fakeClock{now: time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)}
+		playInterruptible(deck, config, strings.NewReader("\n5\nParis\n"), &expiringSleeper{}, printingSpy, nil, watch)
+		expectedLines := []string{"Time left: 30s", "1+4", "Time left: 30s", "Capital of France?"}
+		if !reflect.DeepEqual(printingSpy.lines[1:5], expectedLines) {
+			t.Fatalf("Expected lines %q, got %q", expectedLines, printingSpy.lines)
+		}
+	})
+
+	t.Run("The time left should go down with the clock", func(t *testing.T) {
+		watch := &fakeClock{now: time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)}
+		game := newSession(deck, Config{Timer: 30}, watch)
+		game.start()
+		watch.now = watch.now.Add(12 * time.Second)
+		if left := game.remaining(); left != 18*time.Second {
+			t.Fatalf("Expected 18s left, got %v", left)
+		}
+		watch.now = watch.now.Add(time.Minute)
+		if left := game.remaining(); left != 0 {
+			t.Fatalf("Expected no time left, got %v", left)
+		}
+	})
+
+	t.Run("The player should be warned at every threshold", func(t *testing.T) {
+		input, player := io.Pipe()
+		go io.WriteString(player, "\n")
+		printingSpy := &recordingPrinter{}
+		sleepySpy := &spySleeper{}
+		config := Config{Timer: 30, Warnings: []int{5, 10, 60}}
+		result, _ := playGame(deck, config, input, sleepySpy, printingSpy)
+		if result.Ending != OutOfTime {
+			t.Fatalf("Expected the game to run out of time, got %+v", result)
+		}
+		var warnings []string
+		for _, line := range printingSpy.lines {
+			if strings.HasPrefix(line, "Hurry up") {
+				warnings = append(warnings, line)
+			}
+		}
+		expectedWarnings := []string{"Hurry up, only 10s left!", "Hurry up, only 5s left!"}
+		if !reflect.DeepEqual(warnings, expectedWarnings) {
+			t.Fatalf("Expected warnings %q, got %q", expectedWarnings, warnings)
+		}
+		expectedSleeps := []time.Duration{20 * time.Second, 5 * time.Second, 5 * time.Second}
+		if !reflect.DeepEqual(sleepySpy.args, expectedSleeps) {
+			t.Fatalf("Expected sleeps %v, got %v", expectedSleeps, sleepySpy.args)
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package quiz
+
+// Question is a single question/answer pair of a deck. Line is the line
+// of the source the question was read from (zero if it didn't come from
+// a file), and Meta holds any extra fields the source provides
+type Question struct {
+	Text   string
+	Answer string
+	// Accept lists other answers that are also correct
+	Accept []string
+	// Match names how answers are compared, see ParseMatcher. When it's
+	// empty the matcher is inferred from the answer
+	Match string
+	// Matcher, if set, takes precedence over Match, for custom matchers
+	Matcher Matcher
+	// Options makes this a multiple-choice question: the player picks one
+	// of them by letter, and the answer has to be one of them
+	Options []string
+	// Category groups related questions, e.g. "geography"
+	Category string
+	// Tags are free-form labels for picking questions across categories,
+	// e.g. "europe" or "hard"
+	Tags []string
+	// Points is how much the question is worth
+	Points int
+	// Hint is an optional clue for the player
+	Hint string
+	// Timer, if positive, is how many seconds the player has to answer
+	Timer int
+	// Difficulty ranks the question against the others of its deck, the
+	// higher the harder. Only adaptive games make use of it
+	Difficulty int
+	Line       int
+	Meta       map[string]string
+}
+
+// matcher returns the Matcher used to check answers to q
+func (q Question) matcher() (Matcher, error) {
+	if q.Matcher != nil {
+		return q.Matcher, nil
+	}
+	if q.Match != "" {
+		return ParseMatcher(q.Match)
+	}
+	return inferMatcher(q.Answer), nil
+}
+
+// Check reports whether input is a correct answer to q, i.e. whether it
+// matches the answer or any of the accepted answers
+func (q Question) Check(input string) bool {
+	matcher, err := q.matcher()
+	if err != nil {
+		return false
+	}
+	for _, answer := range q.answers() {
+		if matcher.Match(answer, input) {
+			return true
+		}
+	}
+	return false
+}
+
+// answers lists every answer to q that is correct
+func (q Question) answers() []string {
+	return append([]string{q.Answer}, q.Accept...)
+}
+
+// validate checks that q can be asked: its matcher exists and accepts the
+// shape of its answers, and multiple-choice options make sense
+func (q Question) validate() error {
+	matcher, errMatcher := q.matcher()
+	if errMatcher != nil {
+		return errMatcher
+	}
+	if v, ok := matcher.(validator); ok {
+		for _, answer := range q.answers() {
+			if err := v.Validate(answer); err != nil {
+				return err
+			}
+		}
+	}
+	return q.validateOptions()
+}
+
+// Deck is an ordered collection of questions. The game asks the questions
+// in the order they appear in the deck, and duplicates are kept as is
+type Deck []Question
+
+// Duplicates reports the questions that appear more than once in the
+// deck, mapping the text of every duplicated question to the lines it
+// appears on, in deck order
+func (d Deck) Duplicates() map[string][]int {
+	seen := make(map[string][]int)
+	for _, q := range d {
+		seen[q.Text] = append(seen[q.Text], q.Line)
+	}
+	duplicates := make(map[string][]int)
+	for text, lines := range seen {
+		if len(lines) > 1 {
+			duplicates[text] = lines
+		}
+	}
+	return duplicates
+}
@@ -0,0 +1,355 @@
+package quiz
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Answer records how the player answered a question
+type Answer struct {
+	Question Question
+	Input    string
+	// Choice is the option picked, for multiple-choice questions
+	Choice  string
+	Correct bool
+	// TimedOut is set when the question's time limit ran out before the
+	// player answered
+	TimedOut bool
+	// Duration is how long the player took to answer
+	Duration time.Duration
+	// Attempt counts the times the question was asked, starting from 1.
+	// Only practice mode asks a question more than once
+	Attempt int
+}
+
+// check grades userInput as an answer to question. For multiple-choice
+// questions userInput is the letter of the picked option
+func check(question Question, userInput string) Answer {
+	answer := Answer{Question: question, Input: userInput}
+	if !question.isMultipleChoice() {
+		answer.Correct = question.Check(userInput)
+		return answer
+	}
+	choice, ok := question.choose(userInput)
+	answer.Choice = choice
+	answer.Correct = ok && question.Check(choice)
+	return answer
+}
+
+// session is the state of one player's game: which question is being
+// asked, the answers given so far and how the game ended. It knows
+// nothing about how questions are shown or answers are read, so that the
+// terminal and the web front-ends drive the same engine. Front-ends and
+// timers run concurrently, so it's guarded by a mutex
+type session struct {
+	mu     sync.Mutex
+	config Config
+	// source is the deck the game was made from, before it was arranged
+	source  Deck
+	deck    Deck
+	seed    int64
+	answers []Answer
+	// position is the index in deck of the question being asked
+	position int
+	// questions is the number of questions in the game. In practice mode
+	// missed questions are queued again at the end of deck, so deck can
+	// grow past it
+	questions int
+	// attempts maps the index in deck of every question queued again to
+	// its attempt number
+	attempts map[int]int
+	// pool holds the questions not picked yet in adaptive mode, where
+	// questions are picked one at a time, closest to level first. skill
+	// is the running estimate of the player's skill
+	pool     Deck
+	picked   int
+	level    int
+	minLevel int
+	maxLevel int
+	skill    float64
+	// asked is when the question being asked was first shown
+	asked time.Time
+	// clock tells the time of every timestamp and duration of the game
+	clock clock
+	// limit is the time the game has left when it starts, and elapsed the
+	// time it was played before, if it was resumed. Neither changes once
+	// the session is made
+	limit    time.Duration
+	elapsed  time.Duration
+	started  time.Time
+	duration time.Duration
+	ending   Ending
+	over     bool
+}
+
+// newSession arranges source as config says, ready for a new game timed
+// by watch
+func newSession(source Deck, config Config, watch clock) *session {
+	deck, seed := config.arrange(source, watch)
+	// the session's deck grows in practice mode, and mustn't write into the
+	// deck it was given, which other sessions may share
+	deck = append(Deck(nil), deck...)
+	s := &session{
+		config:    config,
+		source:    source,
+		deck:      deck,
+		seed:      seed,
+		questions: len(deck),
+		attempts:  make(map[int]int),
+		limit:     time.Duration(config.Timer) * time.Second,
+		clock:     watch,
+	}
+	if config.Adaptive {
+		s.startAdaptive(deck)
+	}
+	return s
+}
+
+// start starts the clock of the game
+func (s *session) start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.started = s.clock.Now()
+}
+
+// question returns the question being asked, or false if the game is over
+func (s *session) question() (Question, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.over {
+		return Question{}, false
+	}
+	if s.position >= len(s.deck) && !(s.config.Adaptive && s.pick()) {
+		return Question{}, false
+	}
+	if s.asked.IsZero() {
+		s.asked = s.clock.Now()
+	}
+	return s.deck[s.position], true
+}
+
+// answer grades input as the answer to the question being asked, and
+// moves on to the next question. It returns false if the game is over
+func (s *session) answer(input string) (Answer, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.over || s.position >= len(s.deck) {
+		return Answer{}, false
+	}
+	answer := check(s.deck[s.position], input)
+	s.record(answer)
+	return answer, true
+}
+
+// answerAt is answer, for the question numbered number as progress counts
+// them. It returns false without grading input if that question isn't the
+// one being asked anymore, e.g. when a web form is posted twice
+func (s *session) answerAt(number int, input string) (Answer, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.over || s.position >= len(s.deck) || number != s.position+1 {
+		return Answer{}, false
+	}
+	answer := check(s.deck[s.position], input)
+	s.record(answer)
+	return answer, true
+}
+
+// last returns the latest answer graded, which the web game tells the
+// player about on the next page
+func (s *session) last() (Answer, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.answers) == 0 {
+		return Answer{}, false
+	}
+	return s.answers[len(s.answers)-1], true
+}
+
+// progress returns the number of the question being asked, counting from
+// 1, and the number of questions to ask, which grows in practice mode
+func (s *session) progress() (int, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.position + 1, len(s.deck) + s.picksLeft()
+}
+
+// timeOut marks the question being asked as timed out, and moves on to
+// the next question
+func (s *session) timeOut() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.over || s.position >= len(s.deck) {
+		return
+	}
+	s.record(Answer{Question: s.deck[s.position], TimedOut: true})
+}
+
+// record adds answer to the answers given so far and moves on to the next
+// question. In practice mode a missed question is queued again, until it
+// has been asked Retries more times. s.mu must be held
+func (s *session) record(answer Answer) {
+	if !s.asked.IsZero() {
+		answer.Duration = s.clock.Now().Sub(s.asked)
+	}
+	answer.Attempt = 1
+	if attempt, ok := s.attempts[s.position]; ok {
+		answer.Attempt = attempt
+	}
+	if s.config.Practice && !answer.Correct && answer.Attempt <= s.config.Retries {
+		s.attempts[len(s.deck)] = answer.Attempt + 1
+		s.deck = append(s.deck, answer.Question)
+	}
+	if s.config.Adaptive {
+		s.adapt(answer)
+	}
+	s.answers = append(s.answers, answer)
+	s.position++
+	s.asked = time.Time{}
+}
+
+// expire applies the time limits of the game against the clock: the
+// question being asked times out if its own limit has passed, and the game
+// ends if the limit of the whole game has. Front-ends that don't run
+// timers of their own, like the web one, call it before every step. It
+// reports whether the question being asked timed out
+func (s *session) expire() bool {
+	s.mu.Lock()
+	if s.over || s.started.IsZero() {
+		s.mu.Unlock()
+		return false
+	}
+	timedOut := false
+	if s.position < len(s.deck) && !s.asked.IsZero() {
+		limit := s.config.questionTimer(s.deck[s.position])
+		if limit > 0 && s.clock.Now().Sub(s.asked) > limit {
+			s.record(Answer{Question: s.deck[s.position], TimedOut: true})
+			timedOut = true
+		}
+	}
+	outOfTime := s.limit > 0 && s.clock.Now().Sub(s.started) > s.limit
+	s.mu.Unlock()
+	if outOfTime {
+		s.end(OutOfTime)
+	}
+	return timedOut
+}
+
+// remaining is how much time is left before the game runs out of time,
+// zero if the game has no time limit
+func (s *session) remaining() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.limit <= 0 {
+		return 0
+	}
+	left := s.limit - s.clock.Now().Sub(s.started)
+	if left < 0 {
+		return 0
+	}
+	return left
+}
+
+// stale reports whether the game has been over for longer than grace,
+// e.g. out of time, or started longer than maxAge ago
+func (s *session) stale(grace, maxAge time.Duration) bool {
+	s.expire()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.clock.Now()
+	if s.over {
+		ended := s.started.Add(s.duration - s.elapsed)
+		// a game found out of time late ran out when its limit did
+		if deadline := s.started.Add(s.limit); s.limit > 0 && deadline.Before(ended) {
+			ended = deadline
+		}
+		return now.Sub(ended) > grace
+	}
+	return now.Sub(s.started) > maxAge
+}
+
+// end ends the game. Only the first call counts, so that e.g. the timer
+// running out after the player quit doesn't change how the game ended
+func (s *session) end(ending Ending) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.over {
+		return
+	}
+	s.over = true
+	s.ending = ending
+	s.duration = s.elapsed
+	if !s.started.IsZero() {
+		s.duration += s.clock.Now().Sub(s.started)
+	}
+}
+
+// finished reports whether every question has been asked
+func (s *session) finished() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.position >= len(s.deck) && s.picksLeft() == 0
+}
+
+// isOver reports whether the game has ended
+func (s *session) isOver() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.over
+}
+
+// score is the number of correct answers so far
+func (s *session) score() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var score int
+	for _, answer := range s.answers {
+		if answer.Correct {
+			score++
+		}
+	}
+	return score
+}
+
+// answered returns a copy of the answers given so far
+func (s *session) answered() []Answer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	answers := make([]Answer, len(s.answers))
+	copy(answers, s.answers)
+	return answers
+}
+
+// result sums up the game so far
+func (s *session) result() Result {
+	answers := s.answered()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := Result{
+		Answers:  answers,
+		MaxScore: s.questions,
+		Ending:   s.ending,
+		Seed:     s.seed,
+		Duration: s.duration,
+		Points:   s.config.scorer().Score(answers),
+	}
+	if s.config.Adaptive {
+		result.Skill = math.Round(s.skill*10) / 10
+	}
+	for i := s.position; i < len(s.deck); i++ {
+		// questions queued again in practice mode were answered already
+		if _, retry := s.attempts[i]; !retry {
+			result.Unanswered = append(result.Unanswered, s.deck[i])
+		}
+	}
+	for _, answer := range answers {
+		if answer.Correct {
+			result.Score++
+			if answer.Attempt == 1 {
+				result.FirstTry++
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,49 @@
+package quiz
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ParseError is a problem with a record of a question bank. It unwraps to
+// its cause, so that errors.Is and errors.As can still tell e.g. a record
+// with the wrong columns from an answer that isn't a number
+type ParseError struct {
+	// File is the question bank, when it's known
+	File string
+	// Line and Column locate the problem, starting from 1. JSON and YAML
+	// banks have no columns: Line is the number of the offending entry,
+	// and Column is zero
+	Line   int
+	Column int
+	// Record is the offending record, if it could be read
+	Record []string
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	switch {
+	case e.Column == 0 && len(e.File) == 0:
+		return fmt.Sprintf("entry %d: %v", e.Line, e.Err)
+	case e.Column == 0:
+		return fmt.Sprintf("%s: entry %d: %v", e.File, e.Line, e.Err)
+	case len(e.File) == 0:
+		return fmt.Sprintf("line %d, column %d: %v", e.Line, e.Column, e.Err)
+	default:
+		return fmt.Sprintf("%s:%d:%d: %v", e.File, e.Line, e.Column, e.Err)
+	}
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// inFile records file as the question bank of err, if it's a ParseError
+// that doesn't know its file yet
+func inFile(err error, file string) error {
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) && len(parseErr.File) == 0 {
+		parseErr.File = file
+	}
+	return err
+}
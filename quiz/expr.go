@@ -0,0 +1,185 @@
+package quiz
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+)
+
+var errNotExpression = errors.New("not an arithmetic expression")
+var errDivisionByZero = errors.New("division by zero")
+
+// Evaluate computes the value of an arithmetic expression such as "5+5" or
+// "(3*4)-2", made of numbers, + - * / and parentheses, with the usual
+// precedence. The value is exact, so that e.g. 1/3 isn't rounded
+func Evaluate(expr string) (*big.Rat, error) {
+	p := &exprParser{input: strings.TrimSpace(expr)}
+	if len(p.input) == 0 {
+		return nil, errNotExpression
+	}
+	value, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.input) {
+		return nil, errNotExpression
+	}
+	return value, nil
+}
+
+// exprParser is a recursive descent parser of the grammar
+//
+//	expr   = term { ("+" | "-") term }
+//	term   = factor { ("*" | "/") factor }
+//	factor = ("+" | "-") factor | number | "(" expr ")"
+type exprParser struct {
+	input string
+	pos   int
+}
+
+func (p *exprParser) skipSpace() {
+	for p.pos < len(p.input) && p.input[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// peek returns the next character that isn't a space, or 0 at the end
+func (p *exprParser) peek() byte {
+	p.skipSpace()
+	if p.pos < len(p.input) {
+		return p.input[p.pos]
+	}
+	return 0
+}
+
+func (p *exprParser) expr() (*big.Rat, error) {
+	value, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for op := p.peek(); op == '+' || op == '-'; op = p.peek() {
+		p.pos++
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		if op == '+' {
+			value.Add(value, right)
+		} else {
+			value.Sub(value, right)
+		}
+	}
+	return value, nil
+}
+
+func (p *exprParser) term() (*big.Rat, error) {
+	value, err := p.factor()
+	if err != nil {
+		return nil, err
+	}
+	for op := p.peek(); op == '*' || op == '/'; op = p.peek() {
+		p.pos++
+		right, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		if op == '*' {
+			value.Mul(value, right)
+		} else if right.Sign() == 0 {
+			return nil, errDivisionByZero
+		} else {
+			value.Quo(value, right)
+		}
+	}
+	return value, nil
+}
+
+func (p *exprParser) factor() (*big.Rat, error) {
+	switch c := p.peek(); {
+	case c == '+' || c == '-':
+		p.pos++
+		value, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		if c == '-' {
+			value.Neg(value)
+		}
+		return value, nil
+	case c == '(':
+		p.pos++
+		value, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, errNotExpression
+		}
+		p.pos++
+		return value, nil
+	case c >= '0' && c <= '9' || c == '.':
+		start := p.pos
+		for p.pos < len(p.input) && (p.input[p.pos] >= '0' && p.input[p.pos] <= '9' || p.input[p.pos] == '.') {
+			p.pos++
+		}
+		value, ok := new(big.Rat).SetString(p.input[start:p.pos])
+		if !ok {
+			return nil, errNotExpression
+		}
+		return value, nil
+	}
+	return nil, errNotExpression
+}
+
+// formatValue writes value as an answer: an integer if it is one, and
+// otherwise a decimal rounded to 6 places
+func formatValue(value *big.Rat) string {
+	if value.IsInt() {
+		return value.Num().String()
+	}
+	decimal := strings.TrimRight(value.FloatString(6), "0")
+	return strings.TrimSuffix(decimal, ".")
+}
+
+// computeAnswer fills in the answer of q from its text, when the answer
+// was left blank and the text is an arithmetic expression
+func computeAnswer(q *Question) {
+	if len(q.Answer) > 0 {
+		return
+	}
+	if value, err := Evaluate(q.Text); err == nil {
+		q.Answer = formatValue(value)
+	}
+}
+
+// Mismatch is a question whose answer doesn't match the value of its
+// arithmetic expression
+type Mismatch struct {
+	Question Question
+	// Index is the position of the question in the deck
+	Index int
+	// Expected is the value of the question's expression
+	Expected string
+}
+
+// Verify checks the answer of every question of the deck that is an
+// arithmetic expression against its value, with the question's own
+// matcher. It returns how many questions were checked and the ones whose
+// answer is wrong. Questions that aren't expressions are skipped
+func (d Deck) Verify() (int, []Mismatch) {
+	var checked int
+	var mismatches []Mismatch
+	for i, q := range d {
+		value, err := Evaluate(q.Text)
+		if err != nil {
+			continue
+		}
+		checked++
+		expected := formatValue(value)
+		if !q.Check(expected) {
+			mismatches = append(mismatches, Mismatch{Question: q, Index: i, Expected: expected})
+		}
+	}
+	return checked, mismatches
+}
@@ -0,0 +1,79 @@
+package quiz
+
+import (
+	"math/big"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	t.Run("Expressions should be computed with the usual precedence", func(t *testing.T) {
+		cases := map[string]string{
+			"5+5":         "10",
+			"(3*4)-2":     "10",
+			"3*4-2":       "10",
+			"2+3*4":       "14",
+			"(2+3)*4":     "20",
+			" 8 - 3 - 2 ": "3",
+			"-3+(-2)":     "-5",
+			"12/3/2":      "2",
+			"1/3":         "1/3",
+			"1.5*2":       "3",
+		}
+		for expr, want := range cases {
+			value, err := Evaluate(expr)
+			if err != nil {
+				t.Fatalf("Expected %s to evaluate, got %v", expr, err)
+			}
+			expected, _ := new(big.Rat).SetString(want)
+			if value.Cmp(expected) != 0 {
+				t.Fatalf("Expected %s to be %s, got %s", expr, want, value.RatString())
+			}
+		}
+	})
+
+	t.Run("Anything else should be rejected", func(t *testing.T) {
+		for _, expr := range []string{"", "What is 5+5?", "5+", "(1+2", "1+2)", "2**3", "1..2"} {
+			if _, err := Evaluate(expr); err != errNotExpression {
+				t.Fatalf("Expected %q to be rejected with %v, got %v", expr, errNotExpression, err)
+			}
+		}
+		if _, err := Evaluate("1/(2-2)"); err != errDivisionByZero {
+			t.Fatalf("Expected error %v, got %v", errDivisionByZero, err)
+		}
+	})
+}
+
+func TestVerify(t *testing.T) {
+	deck, err := File(path.Join(testDir, "typos.csv"), false).Questions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Blank answers to arithmetic questions should be computed", func(t *testing.T) {
+		if deck[5].Answer != "14" {
+			t.Fatalf("Expected the answer to 2*(3+4) to be computed as 14, got %q", deck[5].Answer)
+		}
+		bank, err := YAMLSource{Reader: strings.NewReader("- question: 10/4\n")}.Questions()
+		if err != nil || bank[0].Answer != "2.5" {
+			t.Fatalf("Expected the answer to 10/4 to be computed as 2.5, got %v, %v", bank, err)
+		}
+	})
+
+	t.Run("Wrong answers should be reported with their line", func(t *testing.T) {
+		checked, mismatches := deck.Verify()
+		if checked != 6 {
+			t.Fatalf("Expected 6 arithmetic questions to be checked, got %d", checked)
+		}
+		if len(mismatches) != 2 {
+			t.Fatalf("Expected 2 mismatches, got %v", mismatches)
+		}
+		if mismatches[0].Question.Line != 2 || mismatches[0].Expected != "10" {
+			t.Fatalf("Expected 7+3 on line 2 to be 10, got %v", mismatches[0])
+		}
+		if mismatches[1].Question.Line != 7 || mismatches[1].Expected != "5" {
+			t.Fatalf("Expected 8-3 on line 7 to be 5, got %v", mismatches[1])
+		}
+	})
+}
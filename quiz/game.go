@@ -0,0 +1,135 @@
+package quiz
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+var errNoSource = errors.New("game has no question source")
+
+// Clock tells the time and sleeps, so that the timers of a Game can be
+// driven by something else than the wall clock, e.g. in tests
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+}
+
+// Game is a quiz played on any input and output, for embedding the quiz in
+// other programs. It's made by NewGame with Options, and played with Play
+type Game struct {
+	source    QuestionSource
+	config    Config
+	input     io.Reader
+	output    printer
+	sleepy    sleeper
+	clock     clock
+	interrupt <-chan os.Signal
+}
+
+// Option sets up a Game. Options are applied in order, so that e.g.
+// WithTimer after WithConfig overrides the config's Timer
+type Option func(*Game)
+
+// NewGame returns a game set up by options. By default it plays on the
+// terminal, against the wall clock, with the zero Config, and needs
+// WithSource to have questions to ask
+func NewGame(options ...Option) *Game {
+	g := &Game{
+		input:  os.Stdin,
+		output: &realPrinter{},
+		sleepy: &realSleeper{},
+		clock:  &realClock{},
+	}
+	for _, option := range options {
+		option(g)
+	}
+	return g
+}
+
+// WithSource asks the questions of source
+func WithSource(source QuestionSource) Option {
+	return func(g *Game) {
+		g.source = source
+	}
+}
+
+// WithConfig plays with the options in config, replacing any set before
+func WithConfig(config Config) Option {
+	return func(g *Game) {
+		g.config = config
+	}
+}
+
+// WithInput reads the player's answers from input, one per line
+func WithInput(input io.Reader) Option {
+	return func(g *Game) {
+		g.input = input
+	}
+}
+
+// WithOutput writes what the game says to output. Writes are serialized,
+// as the timers of the game write while it waits for answers
+func WithOutput(output io.Writer) Option {
+	return func(g *Game) {
+		g.output = &writerPrinter{w: output}
+	}
+}
+
+// WithClock times the game and its questions with clock
+func WithClock(clock Clock) Option {
+	return func(g *Game) {
+		g.clock = clock
+		g.sleepy = clock
+	}
+}
+
+// WithScorer weighs the answers into points with scorer
+func WithScorer(scorer Scorer) Option {
+	return func(g *Game) {
+		g.config.Scorer = scorer
+	}
+}
+
+// WithTimer gives the whole game limit to be played, to the second. A
+// limit under a second is a second rather than no limit at all
+func WithTimer(limit time.Duration) Option {
+	return func(g *Game) {
+		g.config.Timer = int(limit.Round(time.Second) / time.Second)
+		if limit > 0 && g.config.Timer == 0 {
+			g.config.Timer = 1
+		}
+	}
+}
+
+// WithInterrupt quits the game on any signal received from interrupt, as
+// if the player had entered the quit keyword
+func WithInterrupt(interrupt <-chan os.Signal) Option {
+	return func(g *Game) {
+		g.interrupt = interrupt
+	}
+}
+
+// Play plays the game until every question is asked, the player quits or
+// time runs out, and returns how it went
+func (g *Game) Play() (Result, error) {
+	if g.source == nil {
+		return Result{}, errNoSource
+	}
+	return playInterruptible(g.source, g.config, g.input, g.sleepy, g.output, g.interrupt, g.clock)
+}
+
+// writerPrinter prints lines to an io.Writer
+type writerPrinter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (p *writerPrinter) Println(a ...interface{}) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return fmt.Fprintln(p.w, a...)
+}
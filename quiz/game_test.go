@@ -0,0 +1,125 @@
+package quiz
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stoppedClock never moves, and its sleeps never end unless they're for
+// expire, like expiringSleeper
+type stoppedClock struct {
+	fakeClock
+	expire time.Duration
+}
+
+func (s *stoppedClock) Sleep(d time.Duration) {
+	if d != s.expire {
+		select {}
+	}
+}
+
+// answerCounter scores 10 points for every answer, right or wrong
+type answerCounter struct{}
+
+func (answerCounter) Score(answers []Answer) float64 {
+	return float64(10 * len(answers))
+}
+
+func TestGame(t *testing.T) {
+	deck := Deck{
+		{Text: "1+4", Answer: "5"},
+		{Text: "Capital of France?", Answer: "Paris"},
+	}
+
+	t.Run("A game should play on the input and output it's given", func(t *testing.T) {
+		var output bytes.Buffer
+		game := NewGame(
+			WithSource(deck),
+			WithInput(strings.NewReader("\n5\nRome\n")),
+			WithOutput(&output),
+			WithClock(&stoppedClock{}),
+			WithScorer(answerCounter{}),
+			WithTimer(30*time.Second),
+		)
+		result, err := game.Play()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.Ending != Completed || result.Score != 1 || result.Points != 20 {
+			t.Fatalf("Expected 1 correct answer and 20 points, got %+v", result)
+		}
+		expected := "Thank you for playing. your final score is 1 out of 2 with a weighted score of 20\n"
+		if !strings.HasSuffix(output.String(), expected) {
+			t.Fatalf("Expected the output to end with %q, got %q", expected, output.String())
+		}
+	})
+
+	t.Run("A game should run out of time on its clock", func(t *testing.T) {
+		input, player := io.Pipe()
+		go io.WriteString(player, "\n")
+		game := NewGame(
+			WithSource(deck),
+			WithConfig(Config{Timer: 10, Countdown: true}),
+			WithTimer(5*time.Second),
+			WithInput(input),
+			WithOutput(ioutil.Discard),
+			WithClock(&stoppedClock{expire: 5 * time.Second}),
+		)
+		result, _ := game.Play()
+		if result.Ending != OutOfTime {
+			t.Fatalf("Expected the game to run out of time, got %+v", result)
+		}
+	})
+
+	t.Run("A limit under a second should still be a limit", func(t *testing.T) {
+		game := NewGame(WithTimer(200 * time.Millisecond))
+		if game.config.Timer != 1 {
+			t.Fatalf("Expected a limit of 1 second, got %d", game.config.Timer)
+		}
+	})
+
+	t.Run("A game without a timer should not run out of time", func(t *testing.T) {
+		game := NewGame(
+			WithSource(deck),
+			WithConfig(Config{Countdown: true, Warnings: []int{10}}),
+			WithInput(strings.NewReader("\n5\nParis\n")),
+			WithOutput(ioutil.Discard),
+		)
+		result, _ := game.Play()
+		if result.Ending != Completed || result.Score != 2 {
+			t.Fatalf("Expected a completed game with 2 correct answers, got %+v", result)
+		}
+
+		game = NewGame(WithSource(deck), WithInput(strings.NewReader("\n5\nq\n")), WithOutput(ioutil.Discard))
+		result, _ = game.Play()
+		if result.Snapshot == nil || result.Snapshot.Remaining != 0 {
+			t.Fatalf("Expected a snapshot with no time limit, got %+v", result.Snapshot)
+		}
+	})
+
+	t.Run("A game should leave the input past its end unread", func(t *testing.T) {
+		input, host := io.Pipe()
+		go io.WriteString(host, "\n5\nParis\n")
+		game := NewGame(WithSource(deck), WithInput(input), WithOutput(ioutil.Discard))
+		if result, _ := game.Play(); result.Ending != Completed {
+			t.Fatalf("Expected a completed game, got %+v", result)
+		}
+		go io.WriteString(host, "next\n")
+		line := make([]byte, len("next\n"))
+		if _, err := io.ReadFull(input, line); err != nil || string(line) != "next\n" {
+			t.Fatalf("Expected the host to read %q, got %q and %v", "next\n", line, err)
+		}
+	})
+
+	t.Run("A game without questions should say so", func(t *testing.T) {
+		_, err := NewGame().Play()
+		if !errors.Is(err, errNoSource) {
+			t.Fatalf("Expected errNoSource, got %v", err)
+		}
+	})
+}
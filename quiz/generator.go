@@ -0,0 +1,150 @@
+package quiz
+
+import (
+	"errors"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errBadGenerator = errors.New("generator needs Min <= Max, operators out of + - * /, and a non-zero operand to divide by")
+
+// defaultGenerated is how many problems a Generator makes when its Count
+// isn't set
+const defaultGenerated = 10
+
+// divisorTries is how many operands are drawn, looking for one that
+// divides a problem exactly, before settling for a trivial divisor
+const divisorTries = 20
+
+// Generator is a QuestionSource making up arithmetic problems, such as
+// "(3+4)*2", so that decks of them needn't be written by hand. Division
+// always comes out exact
+type Generator struct {
+	// Count is how many problems to make, 10 if it's not positive
+	Count int
+	// Operators are the operators to draw from, out of "+-*/". All of them
+	// are used when it's empty
+	Operators string
+	// Min and Max bound the operands, inclusively
+	Min, Max int
+	// Difficulty is the number of operations in every problem, 1 if it's
+	// not positive
+	Difficulty int
+	// Seed seeds the problems, so that the same seed makes the same
+	// problems. When it's zero a seed is picked from the clock
+	Seed int64
+}
+
+// Questions makes up the problems
+func (g Generator) Questions() (Deck, error) {
+	operators := g.Operators
+	if len(operators) == 0 {
+		operators = "+-*/"
+	}
+	if g.Min > g.Max || strings.Trim(operators, "+-*/") != "" {
+		return nil, errBadGenerator
+	}
+	if strings.Contains(operators, "/") && g.Min == 0 && g.Max == 0 {
+		return nil, errBadGenerator
+	}
+	count := g.Count
+	if count <= 0 {
+		count = defaultGenerated
+	}
+	difficulty := g.Difficulty
+	if difficulty <= 0 {
+		difficulty = 1
+	}
+	seed := g.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed))
+	deck := make(Deck, 0, count)
+	for i := 0; i < count; i++ {
+		deck = append(deck, g.problem(rng, operators, difficulty))
+	}
+	return deck, nil
+}
+
+// problem makes up a problem of difficulty operations, applied left to
+// right. Parentheses are added where precedence would get in the way
+func (g Generator) problem(rng *rand.Rand, operators string, difficulty int) Question {
+	value := g.operand(rng)
+	text := strconv.Itoa(value)
+	var last byte
+	for i := 0; i < difficulty; i++ {
+		op := operators[rng.Intn(len(operators))]
+		operand := g.operand(rng)
+		switch op {
+		case '+':
+			value += operand
+		case '-':
+			value -= operand
+		case '*':
+			value *= operand
+		case '/':
+			operand = g.divisor(rng)
+			if i == 0 {
+				// the first operand can be picked so that it divides exactly
+				value *= operand
+				text = strconv.Itoa(value)
+			} else {
+				operand = g.divisorOf(rng, value, operand)
+			}
+			value /= operand
+		}
+		if (op == '*' || op == '/') && (last == '+' || last == '-') {
+			text = "(" + text + ")"
+		}
+		text += string(op) + formatOperand(operand)
+		last = op
+	}
+	return Question{Text: text, Answer: strconv.Itoa(value), Category: "arithmetic"}
+}
+
+// operand draws an operand between Min and Max
+func (g Generator) operand(rng *rand.Rand) int {
+	return g.Min + rng.Intn(g.Max-g.Min+1)
+}
+
+// divisor draws a non-zero operand between Min and Max
+func (g Generator) divisor(rng *rand.Rand) int {
+	for {
+		if operand := g.operand(rng); operand != 0 {
+			return operand
+		}
+	}
+}
+
+// divisorOf returns an operand that divides value exactly: operand if it
+// does, another one drawn between Min and Max if one does, or else 1, -1
+// or value itself
+func (g Generator) divisorOf(rng *rand.Rand, value, operand int) int {
+	for i := 0; i < divisorTries; i++ {
+		if value%operand == 0 {
+			return operand
+		}
+		operand = g.divisor(rng)
+	}
+	switch {
+	case g.Min <= 1 && 1 <= g.Max:
+		return 1
+	case g.Min <= -1 && -1 <= g.Max:
+		return -1
+	case value != 0:
+		return value
+	}
+	return operand
+}
+
+// formatOperand writes operand, in parentheses if it's negative so that
+// e.g. 3-(-2) reads unambiguously
+func formatOperand(operand int) string {
+	if operand < 0 {
+		return "(" + strconv.Itoa(operand) + ")"
+	}
+	return strconv.Itoa(operand)
+}
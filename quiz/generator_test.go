@@ -0,0 +1,99 @@
+package quiz
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestGenerator(t *testing.T) {
+	t.Run("The same seed should make the same problems", func(t *testing.T) {
+		generator := Generator{Count: 20, Min: -10, Max: 10, Difficulty: 3, Seed: 42}
+		first, err := generator.Questions()
+		if err != nil {
+			t.Fatal(err)
+		}
+		second, _ := generator.Questions()
+		if !reflect.DeepEqual(first, second) {
+			t.Fatalf("Expected the same problems, got %v and %v", first, second)
+		}
+		generator.Seed = 43
+		third, _ := generator.Questions()
+		if reflect.DeepEqual(first, third) {
+			t.Fatalf("Expected different seeds to make different problems, got %v", third)
+		}
+	})
+
+	t.Run("Problems should use the given operators and operands", func(t *testing.T) {
+		deck, err := Generator{Count: 50, Operators: "+", Min: 1, Max: 9, Seed: 1}.Questions()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(deck) != 50 {
+			t.Fatalf("Expected 50 problems, got %d", len(deck))
+		}
+		for _, question := range deck {
+			operands := strings.Split(question.Text, "+")
+			if len(operands) != 2 {
+				t.Fatalf("Expected a single addition, got %s", question.Text)
+			}
+			sum := 0
+			for _, operand := range operands {
+				n, _ := strconv.Atoi(operand)
+				if n < 1 || n > 9 {
+					t.Fatalf("Expected operands between 1 and 9, got %s", question.Text)
+				}
+				sum += n
+			}
+			if question.Answer != strconv.Itoa(sum) {
+				t.Fatalf("Expected %s to be %d, got %s", question.Text, sum, question.Answer)
+			}
+		}
+	})
+
+	t.Run("Division should always be exact", func(t *testing.T) {
+		deck, err := Generator{Count: 50, Operators: "/", Min: 1, Max: 12, Difficulty: 3, Seed: 7}.Questions()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, question := range deck {
+			operands := strings.Split(question.Text, "/")
+			value, _ := strconv.Atoi(operands[0])
+			for _, operand := range operands[1:] {
+				n, _ := strconv.Atoi(operand)
+				if value%n != 0 {
+					t.Fatalf("Expected %s to divide exactly", question.Text)
+				}
+				value /= n
+			}
+			if question.Answer != strconv.Itoa(value) {
+				t.Fatalf("Expected %s to be %d, got %s", question.Text, value, question.Answer)
+			}
+		}
+	})
+
+	t.Run("Precedence should be made explicit with parentheses", func(t *testing.T) {
+		deck, _ := Generator{Count: 50, Operators: "+*", Min: 1, Max: 9, Difficulty: 2, Seed: 3}.Questions()
+		for _, question := range deck {
+			if strings.Count(question.Text, "+") == 1 && strings.Index(question.Text, "+") < strings.Index(question.Text, "*") &&
+				!strings.HasPrefix(question.Text, "(") {
+				t.Fatalf("Expected the addition to be in parentheses, got %s", question.Text)
+			}
+		}
+	})
+
+	t.Run("Bad settings should be rejected", func(t *testing.T) {
+		generators := []Generator{
+			{Min: 5, Max: 1},
+			{Operators: "+%"},
+			{Operators: "/", Min: 0, Max: 0},
+		}
+		for _, generator := range generators {
+			_, err := generator.Questions()
+			if err != errBadGenerator {
+				t.Fatalf("Expected %v to be rejected, got %v", generator, err)
+			}
+		}
+	})
+}
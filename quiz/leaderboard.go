@@ -0,0 +1,138 @@
+package quiz
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+// leaderboardBucket is the top-level bucket holding a nested bucket of
+// entries for every deck
+var leaderboardBucket = []byte("leaderboard")
+
+// Entry is the record of one game on the leaderboard
+type Entry struct {
+	Player   string        `json:"player"`
+	Deck     string        `json:"deck"`
+	Score    int           `json:"score"`
+	MaxScore int           `json:"max_score"`
+	Duration time.Duration `json:"duration"`
+	Time     time.Time     `json:"time"`
+}
+
+// NewEntry records the result of a game player played on deck, which
+// finished at finished
+func NewEntry(player, deck string, result Result, finished time.Time) Entry {
+	return Entry{
+		Player:   player,
+		Deck:     deck,
+		Score:    result.Score,
+		MaxScore: result.MaxScore,
+		Duration: result.Duration,
+		Time:     finished,
+	}
+}
+
+// ranksAbove reports whether e comes before other on the leaderboard:
+// higher scores first, then the quickest to complete, then the earliest
+func (e Entry) ranksAbove(other Entry) bool {
+	if e.Score != other.Score {
+		return e.Score > other.Score
+	}
+	if e.Duration != other.Duration {
+		return e.Duration < other.Duration
+	}
+	return e.Time.Before(other.Time)
+}
+
+// Leaderboard keeps the entries of every game played in a BoltDB, with
+// a bucket per deck
+type Leaderboard struct {
+	db *bolt.DB
+}
+
+// NewLeaderboard returns a Leaderboard stored in db. Closing db is up to
+// the caller
+func NewLeaderboard(db *bolt.DB) *Leaderboard {
+	return &Leaderboard{db: db}
+}
+
+// Record adds entry to the leaderboard of its deck
+func (l *Leaderboard) Record(entry Entry) error {
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return l.db.Update(func(tx *bolt.Tx) error {
+		root, err := tx.CreateBucketIfNotExists(leaderboardBucket)
+		if err != nil {
+			return err
+		}
+		b, err := root.CreateBucketIfNotExists([]byte(entry.Deck))
+		if err != nil {
+			return err
+		}
+		id, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		key := make([]byte, 8)
+		binary.BigEndian.PutUint64(key, id)
+		return b.Put(key, value)
+	})
+}
+
+// Top returns the n best entries of deck, best first. If n isn't positive
+// every entry is returned
+func (l *Leaderboard) Top(deck string, n int) ([]Entry, error) {
+	entries := make([]Entry, 0, 10)
+	err := l.db.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket(leaderboardBucket)
+		if root == nil {
+			return nil
+		}
+		b := root.Bucket([]byte(deck))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(_, value []byte) error {
+			var entry Entry
+			err := json.Unmarshal(value, &entry)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].ranksAbove(entries[j])
+	})
+	if n > 0 && len(entries) > n {
+		entries = entries[:n]
+	}
+	return entries, nil
+}
+
+// Decks lists the decks that have entries on the leaderboard, sorted by
+// name
+func (l *Leaderboard) Decks() ([]string, error) {
+	decks := make([]string, 0, 10)
+	err := l.db.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket(leaderboardBucket)
+		if root == nil {
+			return nil
+		}
+		return root.ForEach(func(name, _ []byte) error {
+			decks = append(decks, string(name))
+			return nil
+		})
+	})
+	return decks, err
+}
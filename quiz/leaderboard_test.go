@@ -0,0 +1,67 @@
+package quiz
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+func TestLeaderboard(t *testing.T) {
+	db, err := bolt.Open(filepath.Join(t.TempDir(), "leaderboard.db"), 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	leaderboard := NewLeaderboard(db)
+	start := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Player: "ann", Deck: "maths", Score: 8, MaxScore: 10, Duration: 40 * time.Second, Time: start},
+		{Player: "bob", Deck: "maths", Score: 9, MaxScore: 10, Duration: 50 * time.Second, Time: start.Add(time.Hour)},
+		{Player: "cat", Deck: "maths", Score: 8, MaxScore: 10, Duration: 30 * time.Second, Time: start.Add(2 * time.Hour)},
+		{Player: "dan", Deck: "capitals", Score: 3, MaxScore: 5, Duration: 20 * time.Second, Time: start},
+	}
+	for _, entry := range entries {
+		if err := leaderboard.Record(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("Top scores should be ranked by score, then by completion time", func(t *testing.T) {
+		top, err := leaderboard.Top("maths", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		players := make([]string, 0, len(top))
+		for _, entry := range top {
+			players = append(players, entry.Player)
+		}
+		expected := []string{"bob", "cat", "ann"}
+		if !reflect.DeepEqual(players, expected) {
+			t.Fatalf("Expected ranking %v, got %v", expected, players)
+		}
+		if !top[0].Time.Equal(entries[1].Time) || top[0].MaxScore != 10 {
+			t.Fatalf("Expected the entry to be stored as is, got %+v", top[0])
+		}
+	})
+
+	t.Run("Top should return at most n entries", func(t *testing.T) {
+		top, _ := leaderboard.Top("maths", 1)
+		if len(top) != 1 || top[0].Player != "bob" {
+			t.Fatalf("Expected only bob, got %+v", top)
+		}
+	})
+
+	t.Run("Each deck should have its own leaderboard", func(t *testing.T) {
+		decks, _ := leaderboard.Decks()
+		if !reflect.DeepEqual(decks, []string{"capitals", "maths"}) {
+			t.Fatalf("Expected decks capitals and maths, got %v", decks)
+		}
+		top, _ := leaderboard.Top("history", 0)
+		if len(top) != 0 {
+			t.Fatalf("Expected no entries for an unknown deck, got %+v", top)
+		}
+	})
+}
@@ -0,0 +1,195 @@
+package quiz
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Issue is a problem found in a question bank by Lint
+type Issue struct {
+	File string
+	// Line and Column locate the problem, starting from 1. JSON and YAML
+	// banks have no columns: Line is the number of the entry, and Column
+	// is zero. Both are zero for problems with the bank as a whole
+	Line    int
+	Column  int
+	Message string
+}
+
+func (i Issue) String() string {
+	switch {
+	case i.Line == 0:
+		return fmt.Sprintf("%s: %s", i.File, i.Message)
+	case i.Column == 0:
+		return fmt.Sprintf("%s: entry %d: %s", i.File, i.Line, i.Message)
+	default:
+		return fmt.Sprintf("%s:%d:%d: %s", i.File, i.Line, i.Column, i.Message)
+	}
+}
+
+// Lint reads the CSV question bank of reader, named file in the issues, and
+// reports every problem it finds instead of stopping at the first one like
+// the game does: records with the wrong columns, answers that don't suit
+// their question, empty questions and answers, stray whitespace and
+// duplicate questions. The issues are sorted by line and column. The
+// error is only set if reader itself fails
+func Lint(file string, reader io.Reader, header bool) ([]Issue, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	var issues []Issue
+	report := func(line, column int, format string, a ...interface{}) {
+		issues = append(issues, Issue{File: file, Line: line, Column: column, Message: fmt.Sprintf(format, a...)})
+	}
+
+	// seen maps the text of every question to the line it first appears on
+	seen := make(map[string]int)
+	for first := true; ; first = false {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			report(parseErr.Line, parseErr.Column, "%v", parseErr.Err)
+			continue
+		}
+		if err != nil {
+			return issues, err
+		}
+		if first && header {
+			continue
+		}
+		line, _ := csvReader.FieldPos(0)
+		column := func(field int) int {
+			_, column := csvReader.FieldPos(field)
+			return column
+		}
+
+		for i, field := range record {
+			if trimmed := strings.TrimSpace(field); len(trimmed) > 0 && trimmed != field {
+				report(line, column(i), "stray whitespace around %q", trimmed)
+			}
+		}
+		if len(strings.TrimSpace(record[0])) == 0 {
+			report(line, column(0), "empty question")
+		} else if firstLine, ok := seen[record[0]]; ok {
+			report(line, column(0), "duplicate of the question on line %d", firstLine)
+		} else {
+			seen[record[0]] = line
+		}
+		if len(record) < 2 {
+			report(line, column(0), "wrong number of columns, expected question, answer and optional key=value columns")
+			continue
+		}
+
+		columnsOK := true
+		for i, field := range record[2:] {
+			key, value, found := strings.Cut(field, "=")
+			key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+			switch {
+			case !found:
+				report(line, column(i+2), "column %q isn't a key=value pair", field)
+				columnsOK = false
+			case key == "points" || key == "timer" || key == "difficulty":
+				if _, err := strconv.Atoi(value); err != nil {
+					report(line, column(i+2), "%s %q isn't a whole number", key, value)
+					columnsOK = false
+				}
+			}
+		}
+		if !columnsOK {
+			continue
+		}
+		_, err = extractQA(record, csvReader.FieldPos)
+		var extractErr *ParseError
+		switch {
+		case errors.Is(err, strconv.ErrSyntax):
+			report(line, column(1), "answer %q doesn't suit the question's matcher", strings.TrimSpace(record[1]))
+		case errors.Is(err, errMissingField):
+			// an empty question is reported above
+			if len(strings.TrimSpace(record[1])) == 0 {
+				report(line, column(1), "empty answer")
+			}
+		case errors.As(err, &extractErr):
+			report(extractErr.Line, extractErr.Column, "%v", extractErr.Err)
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Line != issues[j].Line {
+			return issues[i].Line < issues[j].Line
+		}
+		return issues[i].Column < issues[j].Column
+	})
+	return issues, nil
+}
+
+// LintFile lints the question bank at path. CSV banks are linted with
+// Lint, and JSON and YAML banks entry by entry, with the same checks. A
+// JSON or YAML bank that can't be decoded at all has a single issue
+func LintFile(path string, header bool) ([]Issue, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var entries []entry
+	switch source := sourceFor(path, file, header).(type) {
+	case JSONSource:
+		entries, err = source.entries()
+	case YAMLSource:
+		entries, err = source.entries()
+	default:
+		return Lint(path, file, header)
+	}
+	if err != nil {
+		return []Issue{{File: path, Message: err.Error()}}, nil
+	}
+	return lintEntries(path, entries), nil
+}
+
+// lintEntries reports every problem of the entries of a JSON or YAML
+// bank, with the number of the entry as its line
+func lintEntries(file string, entries []entry) []Issue {
+	var issues []Issue
+	report := func(number int, format string, a ...interface{}) {
+		issues = append(issues, Issue{File: file, Line: number, Message: fmt.Sprintf(format, a...)})
+	}
+
+	// seen maps the text of every question to the entry it first appears in
+	seen := make(map[string]int)
+	for i, e := range entries {
+		number := i + 1
+		for _, field := range []string{e.Question, string(e.Answer)} {
+			if trimmed := strings.TrimSpace(field); len(trimmed) > 0 && trimmed != field {
+				report(number, "stray whitespace around %q", trimmed)
+			}
+		}
+		if len(strings.TrimSpace(e.Question)) == 0 {
+			report(number, "empty question")
+		} else if first, ok := seen[e.Question]; ok {
+			report(number, "duplicate of the question of entry %d", first)
+		} else {
+			seen[e.Question] = number
+		}
+		_, err := toQuestion(e)
+		switch {
+		case errors.Is(err, strconv.ErrSyntax):
+			report(number, "answer %q doesn't suit the question's matcher", strings.TrimSpace(string(e.Answer)))
+		case errors.Is(err, errMissingField):
+			// an empty question is reported above
+			if len(strings.TrimSpace(string(e.Answer))) == 0 {
+				report(number, "empty answer")
+			}
+		case err != nil:
+			report(number, "%v", err)
+		}
+	}
+	return issues
+}
@@ -0,0 +1,86 @@
+package quiz
+
+import (
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	t.Run("Every problem should be reported with its line and column", func(t *testing.T) {
+		file := path.Join(testDir, "lint.csv")
+		issues, err := LintFile(file, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		type position struct{ line, column int }
+		got := make([]position, 0, len(issues))
+		for _, issue := range issues {
+			if issue.File != file {
+				t.Fatalf("Expected issues in %s, got %v", file, issue)
+			}
+			got = append(got, position{issue.Line, issue.Column})
+		}
+		want := []position{{2, 8}, {3, 1}, {4, 1}, {5, 5}, {6, 7}, {7, 1}, {8, 1}}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("Expected issues at %v, got %v", want, issues)
+		}
+		if !strings.Contains(issues[3].Message, `"four"`) {
+			t.Fatalf("Expected the bad answer to be quoted, got %v", issues[3])
+		}
+		if !strings.Contains(issues[5].Message, "duplicate") || !strings.Contains(issues[5].String(), "lint.csv:7:1:") {
+			t.Fatalf("Expected line 7 to be reported as a duplicate, got %v", issues[5])
+		}
+	})
+
+	t.Run("A clean deck should have no issues", func(t *testing.T) {
+		issues, err := Lint("clean.csv", strings.NewReader("question,answer\n5+5,10\n\"Capital of France, sir?\",Paris,category=geography\n"), true)
+		if err != nil || len(issues) != 0 {
+			t.Fatalf("Expected no issues, got %v, %v", issues, err)
+		}
+	})
+
+	t.Run("Blank answers should be reported unless they're computed", func(t *testing.T) {
+		issues, _ := Lint("blank.csv", strings.NewReader("9+1,\nCapital of France?,\n"), false)
+		if len(issues) != 1 || issues[0].Line != 2 || issues[0].Message != "empty answer" {
+			t.Fatalf("Expected an empty answer on line 2, got %v", issues)
+		}
+	})
+
+	t.Run("Every problem of a YAML bank should be reported with its entry", func(t *testing.T) {
+		file := path.Join(testDir, "lint.yaml")
+		issues, err := LintFile(file, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		type problem struct {
+			entry   int
+			message string
+		}
+		got := make([]problem, 0, len(issues))
+		for _, issue := range issues {
+			got = append(got, problem{issue.Line, issue.Message})
+		}
+		want := []problem{
+			{2, "empty answer"},
+			{3, "empty question"},
+			{4, errUnknownMatcher.Error()},
+			{5, `stray whitespace around "Paris"`},
+			{5, "duplicate of the question of entry 1"},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("Expected issues %v, got %v", want, issues)
+		}
+		if !strings.Contains(issues[0].String(), "lint.yaml: entry 2: ") {
+			t.Fatalf("Expected the entry in the issue, got %v", issues[0])
+		}
+	})
+
+	t.Run("A bank that can't be decoded should have a single issue", func(t *testing.T) {
+		issues, err := LintFile(path.Join(testDir, "messages.yaml"), false)
+		if err != nil || len(issues) != 1 || issues[0].Line != 0 {
+			t.Fatalf("Expected a single issue with the whole bank, got %v, %v", issues, err)
+		}
+	})
+}
@@ -0,0 +1,157 @@
+package quiz
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var errUnknownMatcher = errors.New("unknown matcher, expected one of int, float, float:<tolerance>, text or exact")
+
+// defaultTolerance is how far off a float answer can be when no tolerance
+// is given, enough to forgive rounding in the last few digits
+const defaultTolerance = 1e-9
+
+// Matcher decides whether the player's input is a correct answer
+type Matcher interface {
+	Match(answer, input string) bool
+}
+
+// validator is implemented by matchers that only make sense for some
+// answers, e.g. numbers, so that decks can be checked when they're loaded
+type validator interface {
+	Validate(answer string) error
+}
+
+// IntMatcher accepts input that is the same integer as the answer
+type IntMatcher struct{}
+
+// Match compares answer and input as integers, ignoring surrounding spaces
+func (IntMatcher) Match(answer, input string) bool {
+	want, errAnswer := strconv.Atoi(strings.TrimSpace(answer))
+	got, errInput := strconv.Atoi(strings.TrimSpace(input))
+	return errAnswer == nil && errInput == nil && want == got
+}
+
+// Validate returns an error if answer isn't an integer
+func (IntMatcher) Validate(answer string) error {
+	_, err := strconv.Atoi(strings.TrimSpace(answer))
+	return err
+}
+
+// FloatMatcher accepts input that is within Tolerance of the answer
+type FloatMatcher struct {
+	Tolerance float64
+}
+
+// Match compares answer and input as floats, ignoring surrounding spaces
+func (f FloatMatcher) Match(answer, input string) bool {
+	want, errAnswer := strconv.ParseFloat(strings.TrimSpace(answer), 64)
+	got, errInput := strconv.ParseFloat(strings.TrimSpace(input), 64)
+	return errAnswer == nil && errInput == nil && math.Abs(want-got) <= f.Tolerance
+}
+
+// Validate returns an error if answer isn't a number
+func (FloatMatcher) Validate(answer string) error {
+	_, err := strconv.ParseFloat(strings.TrimSpace(answer), 64)
+	return err
+}
+
+// TextMatcher compares answers as text. With the zero value the input has
+// to be exactly the answer; each field relaxes the comparison
+type TextMatcher struct {
+	// FoldCase ignores the difference between upper and lower case
+	FoldCase bool
+	// TrimSpace ignores surrounding spaces and collapses runs of spaces
+	TrimSpace bool
+	// Normalize compares the Unicode NFKC forms, so that e.g. a precomposed
+	// "é" matches "e" followed by a combining accent
+	Normalize bool
+}
+
+// Match compares answer and input as text
+func (t TextMatcher) Match(answer, input string) bool {
+	answer, input = t.normalize(answer), t.normalize(input)
+	if t.FoldCase {
+		return strings.EqualFold(answer, input)
+	}
+	return answer == input
+}
+
+func (t TextMatcher) normalize(s string) string {
+	if t.Normalize {
+		s = norm.NFKC.String(s)
+	}
+	if t.TrimSpace {
+		s = strings.Join(strings.Fields(s), " ")
+	}
+	return s
+}
+
+// ParseMatcher returns the Matcher named by spec, one of
+//
+//	int             the answer and input are the same integer
+//	float           the answer and input are the same number
+//	float:0.01      the input is within 0.01 of the answer
+//	text            the answer and input are the same text, ignoring
+//	                case, spacing and Unicode normalisation form
+//	exact           the answer and input are exactly the same text
+func ParseMatcher(spec string) (Matcher, error) {
+	kind, arg := spec, ""
+	if i := strings.Index(spec, ":"); i >= 0 {
+		kind, arg = spec[:i], spec[i+1:]
+	}
+	switch strings.ToLower(strings.TrimSpace(kind)) {
+	case "int":
+		if arg == "" {
+			return IntMatcher{}, nil
+		}
+	case "float":
+		if arg == "" {
+			return FloatMatcher{Tolerance: defaultTolerance}, nil
+		}
+		tolerance, err := strconv.ParseFloat(strings.TrimSpace(arg), 64)
+		if err != nil || tolerance < 0 {
+			return nil, errUnknownMatcher
+		}
+		return FloatMatcher{Tolerance: tolerance}, nil
+	case "text":
+		if arg == "" {
+			return TextMatcher{FoldCase: true, TrimSpace: true, Normalize: true}, nil
+		}
+	case "exact":
+		if arg == "" {
+			return TextMatcher{}, nil
+		}
+	}
+	return nil, errUnknownMatcher
+}
+
+// isDecimal reports whether answer is a finite number written in decimal,
+// e.g. 2.5 or 1e-3. strconv.ParseFloat alone would also take words like
+// NaN or Inf, which are better compared as text
+func isDecimal(answer string) bool {
+	answer = strings.TrimSpace(answer)
+	if strings.Trim(answer, "0123456789+-.eE") != "" {
+		return false
+	}
+	_, err := strconv.ParseFloat(answer, 64)
+	return err == nil
+}
+
+// inferMatcher picks a matcher from the shape of answer when the question
+// doesn't name one: integers and other numbers are compared as such, and
+// everything else as text
+func inferMatcher(answer string) Matcher {
+	if (IntMatcher{}).Validate(answer) == nil {
+		return IntMatcher{}
+	}
+	if isDecimal(answer) {
+		return FloatMatcher{Tolerance: defaultTolerance}
+	}
+	matcher, _ := ParseMatcher("text")
+	return matcher
+}
@@ -0,0 +1,306 @@
+package quiz
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+var errUnknownLanguage = errors.New("no messages for this language")
+
+// Messages is the catalog of what the game says to the player, so that it
+// can be translated or reworded. Messages are text/template templates
+// filled with a MessageData, e.g. "{{.Score}} out of {{.MaxScore}}". Quit
+// is the keyword the player enters to quit the game
+type Messages struct {
+	Greeting        string `json:"greeting" yaml:"greeting"`
+	Bye             string `json:"bye" yaml:"bye"`
+	TimeOut         string `json:"time_out" yaml:"time_out"`
+	QuestionTimeOut string `json:"question_time_out" yaml:"question_time_out"`
+	Solution        string `json:"solution" yaml:"solution"`
+	Hint            string `json:"hint" yaml:"hint"`
+	Picked          string `json:"picked" yaml:"picked"`
+	Category        string `json:"category" yaml:"category"`
+	FirstTry        string `json:"first_try" yaml:"first_try"`
+	Skill           string `json:"skill" yaml:"skill"`
+	Seed            string `json:"seed" yaml:"seed"`
+	NothingDue      string `json:"nothing_due" yaml:"nothing_due"`
+	Countdown       string `json:"countdown" yaml:"countdown"`
+	Warning         string `json:"warning" yaml:"warning"`
+	Quit            string `json:"quit" yaml:"quit"`
+	// the web pages
+	Progress     string `json:"progress" yaml:"progress"`
+	QuestionTime string `json:"question_time" yaml:"question_time"`
+	Questions    string `json:"questions" yaml:"questions"`
+	OutOfTime    string `json:"out_of_time" yaml:"out_of_time"`
+	StartButton  string `json:"start_button" yaml:"start_button"`
+	AnswerButton string `json:"answer_button" yaml:"answer_button"`
+	QuitButton   string `json:"quit_button" yaml:"quit_button"`
+	PlayAgain    string `json:"play_again" yaml:"play_again"`
+	// the lobby and rooms of multiplayer games
+	LobbyWelcome    string `json:"lobby_welcome" yaml:"lobby_welcome"`
+	LobbyMenu       string `json:"lobby_menu" yaml:"lobby_menu"`
+	NoSuchRoom      string `json:"no_such_room" yaml:"no_such_room"`
+	RoomCreated     string `json:"room_created" yaml:"room_created"`
+	RoomJoined      string `json:"room_joined" yaml:"room_joined"`
+	RoomLeft        string `json:"room_left" yaml:"room_left"`
+	Waiting         string `json:"waiting" yaml:"waiting"`
+	AlreadyAnswered string `json:"already_answered" yaml:"already_answered"`
+	Wrong           string `json:"wrong" yaml:"wrong"`
+	Right           string `json:"right" yaml:"right"`
+	Nobody          string `json:"nobody" yaml:"nobody"`
+	Scoreboard      string `json:"scoreboard" yaml:"scoreboard"`
+	Rank            string `json:"rank" yaml:"rank"`
+	GameOver        string `json:"game_over" yaml:"game_over"`
+	NoQuestions     string `json:"no_questions" yaml:"no_questions"`
+}
+
+// MessageData is what messages can refer to. Fields that don't make sense
+// for a message are left zero, e.g. Answer is only set for Solution
+type MessageData struct {
+	Score    int
+	MaxScore int
+	Points   float64
+	FirstTry int
+	Skill    float64
+	// Time is the time limit of the game in the greeting, the time left in
+	// the countdown and warnings, the time limit of the question on the web
+	// and how long the game was played for at the end, to the second
+	Time time.Duration
+	Seed int64
+	// Question, Answer, Hint and Choice are the question at hand, its
+	// answer and hint, and the option the player picked
+	Question string
+	Answer   string
+	Hint     string
+	Choice   string
+	Category string
+	Quit     string
+	// Number is the number of the question being asked, or the rank of a
+	// player on the scoreboard, and Total the number of questions
+	Number int
+	Total  int
+	// Player and Room are the name of a player and the code of their room,
+	// in multiplayer games
+	Player string
+	Room   string
+}
+
+// English is the catalog the game uses unless told otherwise
+var English = Messages{
+	Greeting:        "Welcome to the maths quiz! Press any button to continue, or enter '{{.Quit}}' at any time to exit",
+	Bye:             "Thank you for playing. your final score is {{.Score}} out of {{.MaxScore}} with a weighted score of {{.Points}}",
+	TimeOut:         "You ran out of time. Thank you for playing. Your final score is {{.Score}} out of {{.MaxScore}} with a weighted score of {{.Points}}",
+	QuestionTimeOut: "Out of time for this question, moving on",
+	Solution:        "Not quite, the answer is {{.Answer}}",
+	Hint:            "Hint: {{.Hint}}",
+	Picked:          "{{.Question}} you picked {{.Choice}}",
+	Category:        "{{.Category}}: {{.Score}} out of {{.MaxScore}}",
+	FirstTry:        "Right on the first try: {{.FirstTry}} out of {{.MaxScore}}",
+	Skill:           "Estimated skill: {{.Skill}}",
+	Seed:            "To replay this session, use the seed {{.Seed}}",
+	NothingDue:      "Nothing to review for now",
+	Countdown:       "Time left: {{.Time}}",
+	Warning:         "Hurry up, only {{.Time}} left!",
+	Quit:            "q",
+	Progress:        "Question {{.Number}} of {{.Total}}",
+	QuestionTime:    "{{.Time}} for this question",
+	Questions:       "{{.Total}} questions{{if .Time}}, {{.Time}} to answer them{{end}}",
+	OutOfTime:       "out of time",
+	StartButton:     "Start",
+	AnswerButton:    "Answer",
+	QuitButton:      "Quit",
+	PlayAgain:       "Play again",
+	LobbyWelcome:    "Welcome to the quiz! What's your name?",
+	LobbyMenu:       "Enter 'create' to host a new room, 'join <code>' to join one, or '{{.Quit}}' to exit",
+	NoSuchRoom:      "There's no room with that code",
+	RoomCreated:     "Created room {{.Room}}. Tell the other players to join it, and enter 'start' when everyone is in",
+	RoomJoined:      "{{.Player}} joined room {{.Room}}",
+	RoomLeft:        "{{.Player}} left the room",
+	Waiting:         "Waiting for the host to start the game",
+	AlreadyAnswered: "You already answered this question, wait for the next one",
+	Wrong:           "Wrong!",
+	Right:           "{{.Player}} got it! The answer was {{.Answer}}",
+	Nobody:          "Nobody got it. The answer was {{.Answer}}",
+	Scoreboard:      "Scoreboard:",
+	Rank:            "{{.Number}}. {{.Player}} {{.Score}}",
+	GameOver:        "Game over! The host can enter 'start' to play again",
+	NoQuestions:     "There are no questions to play, check the question bank and its filters",
+}
+
+var french = Messages{
+	Greeting:        "Bienvenue au quiz de maths ! Appuyez sur Entrée pour commencer, ou tapez '{{.Quit}}' à tout moment pour quitter",
+	Bye:             "Merci d'avoir joué. Votre score final est de {{.Score}} sur {{.MaxScore}}, avec un score pondéré de {{.Points}}",
+	TimeOut:         "Le temps est écoulé. Merci d'avoir joué. Votre score final est de {{.Score}} sur {{.MaxScore}}, avec un score pondéré de {{.Points}}",
+	QuestionTimeOut: "Temps écoulé pour cette question, on passe à la suivante",
+	Solution:        "Pas tout à fait, la réponse est {{.Answer}}",
+	Hint:            "Indice : {{.Hint}}",
+	Picked:          "{{.Question}} vous avez choisi {{.Choice}}",
+	Category:        "{{.Category}} : {{.Score}} sur {{.MaxScore}}",
+	FirstTry:        "Du premier coup : {{.FirstTry}} sur {{.MaxScore}}",
+	Skill:           "Niveau estimé : {{.Skill}}",
+	Seed:            "Pour rejouer cette partie, utilisez la graine {{.Seed}}",
+	NothingDue:      "Rien à réviser pour le moment",
+	Countdown:       "Temps restant : {{.Time}}",
+	Warning:         "Dépêchez-vous, plus que {{.Time}} !",
+	Quit:            "q",
+	Progress:        "Question {{.Number}} sur {{.Total}}",
+	QuestionTime:    "{{.Time}} pour cette question",
+	Questions:       "{{.Total}} questions{{if .Time}}, {{.Time}} pour y répondre{{end}}",
+	OutOfTime:       "temps écoulé",
+	StartButton:     "Commencer",
+	AnswerButton:    "Répondre",
+	QuitButton:      "Quitter",
+	PlayAgain:       "Rejouer",
+	LobbyWelcome:    "Bienvenue au quiz ! Quel est votre nom ?",
+	LobbyMenu:       "Tapez 'create' pour ouvrir une salle, 'join <code>' pour en rejoindre une, ou '{{.Quit}}' pour quitter",
+	NoSuchRoom:      "Il n'y a pas de salle avec ce code",
+	RoomCreated:     "Salle {{.Room}} créée. Donnez le code aux autres joueurs, et tapez 'start' quand tout le monde est là",
+	RoomJoined:      "{{.Player}} a rejoint la salle {{.Room}}",
+	RoomLeft:        "{{.Player}} a quitté la salle",
+	Waiting:         "En attente du lancement de la partie par l'hôte",
+	AlreadyAnswered: "Vous avez déjà répondu à cette question, attendez la suivante",
+	Wrong:           "Faux !",
+	Right:           "{{.Player}} a trouvé ! La réponse était {{.Answer}}",
+	Nobody:          "Personne n'a trouvé. La réponse était {{.Answer}}",
+	Scoreboard:      "Classement :",
+	Rank:            "{{.Number}}. {{.Player}} {{.Score}}",
+	GameOver:        "Partie terminée ! L'hôte peut taper 'start' pour rejouer",
+	NoQuestions:     "Il n'y a aucune question à poser, vérifiez la banque de questions et ses filtres",
+}
+
+var spanish = Messages{
+	Greeting:        "¡Bienvenido al quiz de matemáticas! Pulsa Intro para empezar, o escribe '{{.Quit}}' en cualquier momento para salir",
+	Bye:             "Gracias por jugar. Tu puntuación final es {{.Score}} de {{.MaxScore}}, con una puntuación ponderada de {{.Points}}",
+	TimeOut:         "Se acabó el tiempo. Gracias por jugar. Tu puntuación final es {{.Score}} de {{.MaxScore}}, con una puntuación ponderada de {{.Points}}",
+	QuestionTimeOut: "Se acabó el tiempo para esta pregunta, pasamos a la siguiente",
+	Solution:        "No exactamente, la respuesta es {{.Answer}}",
+	Hint:            "Pista: {{.Hint}}",
+	Picked:          "{{.Question}} elegiste {{.Choice}}",
+	Category:        "{{.Category}}: {{.Score}} de {{.MaxScore}}",
+	FirstTry:        "Al primer intento: {{.FirstTry}} de {{.MaxScore}}",
+	Skill:           "Nivel estimado: {{.Skill}}",
+	Seed:            "Para repetir esta partida, usa la semilla {{.Seed}}",
+	NothingDue:      "Nada que repasar por ahora",
+	Countdown:       "Tiempo restante: {{.Time}}",
+	Warning:         "¡Date prisa, solo quedan {{.Time}}!",
+	Quit:            "s",
+	Progress:        "Pregunta {{.Number}} de {{.Total}}",
+	QuestionTime:    "{{.Time}} para esta pregunta",
+	Questions:       "{{.Total}} preguntas{{if .Time}}, {{.Time}} para responderlas{{end}}",
+	OutOfTime:       "sin tiempo",
+	StartButton:     "Empezar",
+	AnswerButton:    "Responder",
+	QuitButton:      "Salir",
+	PlayAgain:       "Jugar otra vez",
+	LobbyWelcome:    "¡Bienvenido al quiz! ¿Cómo te llamas?",
+	LobbyMenu:       "Escribe 'create' para abrir una sala, 'join <código>' para unirte a una, o '{{.Quit}}' para salir",
+	NoSuchRoom:      "No hay ninguna sala con ese código",
+	RoomCreated:     "Sala {{.Room}} creada. Pasa el código a los demás jugadores, y escribe 'start' cuando estén todos",
+	RoomJoined:      "{{.Player}} se unió a la sala {{.Room}}",
+	RoomLeft:        "{{.Player}} salió de la sala",
+	Waiting:         "Esperando a que el anfitrión empiece la partida",
+	AlreadyAnswered: "Ya respondiste a esta pregunta, espera a la siguiente",
+	Wrong:           "¡Incorrecto!",
+	Right:           "¡{{.Player}} acertó! La respuesta era {{.Answer}}",
+	Nobody:          "Nadie acertó. La respuesta era {{.Answer}}",
+	Scoreboard:      "Clasificación:",
+	Rank:            "{{.Number}}. {{.Player}} {{.Score}}",
+	GameOver:        "¡Fin de la partida! El anfitrión puede escribir 'start' para jugar otra vez",
+	NoQuestions:     "No hay preguntas que hacer, revisa el banco de preguntas y sus filtros",
+}
+
+// catalogs are the built-in catalogs by language code
+var catalogs = map[string]Messages{
+	"en": English,
+	"fr": french,
+	"es": spanish,
+}
+
+// Catalog returns the built-in messages of lang, e.g. "fr". Languages are
+// en, fr and es
+func Catalog(lang string) (Messages, error) {
+	messages, ok := catalogs[strings.ToLower(lang)]
+	if !ok {
+		return Messages{}, fmt.Errorf("%w: %q", errUnknownLanguage, lang)
+	}
+	return messages, nil
+}
+
+// LoadMessages reads a catalog from the file at path, in JSON if it ends
+// in .json and in YAML otherwise. Messages left out of the file fall back
+// to English, and every message is checked to be a valid template
+func LoadMessages(path string) (Messages, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Messages{}, err
+	}
+	var messages Messages
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		err = json.Unmarshal(data, &messages)
+	} else {
+		err = yaml.UnmarshalStrict(data, &messages)
+	}
+	if err != nil {
+		return Messages{}, fmt.Errorf("%s: %w", path, err)
+	}
+	messages = messages.withDefaults()
+	if err := messages.validate(); err != nil {
+		return Messages{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return messages, nil
+}
+
+// withDefaults fills the messages left empty with the English ones
+func (m Messages) withDefaults() Messages {
+	value := reflect.ValueOf(&m).Elem()
+	defaults := reflect.ValueOf(English)
+	for i := 0; i < value.NumField(); i++ {
+		if value.Field(i).String() == "" {
+			value.Field(i).Set(defaults.Field(i))
+		}
+	}
+	return m
+}
+
+// validate checks that every message is a template that can be filled
+// with a MessageData
+func (m Messages) validate() error {
+	value := reflect.ValueOf(m)
+	for i := 0; i < value.NumField(); i++ {
+		name := value.Type().Field(i).Name
+		templ, err := template.New(name).Parse(value.Field(i).String())
+		if err != nil {
+			return err
+		}
+		err = templ.Execute(ioutil.Discard, MessageData{})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fill fills message with data, always with the quit keyword. A message
+// that isn't a valid template is said as is
+func (m Messages) fill(message string, data MessageData) string {
+	data.Quit = m.Quit
+	templ, err := template.New("message").Parse(message)
+	if err != nil {
+		return message
+	}
+	var filled bytes.Buffer
+	if err := templ.Execute(&filled, data); err != nil {
+		return message
+	}
+	return filled.String()
+}
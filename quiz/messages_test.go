@@ -0,0 +1,76 @@
+package quiz
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMessages(t *testing.T) {
+	deck := Deck{
+		{Text: "1+4", Answer: "5"},
+		{Text: "Capital of France?", Answer: "Paris"},
+	}
+	noTimeout := &expiringSleeper{}
+
+	t.Run("Catalog should return the built-in messages of a language", func(t *testing.T) {
+		messages, err := Catalog("fr")
+		if err != nil || messages.Quit != "q" || !strings.HasPrefix(messages.Greeting, "Bienvenue") {
+			t.Fatalf("Expected the French messages, got %+v and %v", messages, err)
+		}
+		_, err = Catalog("xx")
+		if !errors.Is(err, errUnknownLanguage) {
+			t.Fatalf("Expected errUnknownLanguage, got %v", err)
+		}
+	})
+
+	t.Run("Built-in catalogs should have every message", func(t *testing.T) {
+		for lang, messages := range catalogs {
+			value := reflect.ValueOf(messages)
+			for i := 0; i < value.NumField(); i++ {
+				if value.Field(i).String() == "" {
+					t.Fatalf("Expected %s to have a %s message", lang, value.Type().Field(i).Name)
+				}
+			}
+			if err := messages.validate(); err != nil {
+				t.Fatalf("Expected the %s messages to be valid templates, got %v", lang, err)
+			}
+		}
+	})
+
+	t.Run("A game should say the messages of its catalog", func(t *testing.T) {
+		messages, err := LoadMessages(filepath.Join(testDir, "messages.yaml"))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		printingSpy := &recordingPrinter{}
+		config := Config{Timer: 30, Messages: messages}
+		result, _ := playGame(deck, config, strings.NewReader("\n5\nende\n"), noTimeout, printingSpy)
+		if result.Ending != Quit || result.Score != 1 {
+			t.Fatalf("Expected a game quit with the custom keyword, got %+v", result)
+		}
+		expectedLines := []string{"Willkommen! ende zum Beenden, du hast 30s", "1+4", "Capital of France?", "Danke! 1 von 2 in 0s"}
+		if !reflect.DeepEqual(printingSpy.lines, expectedLines) {
+			t.Fatalf("Expected lines %q, got %q", expectedLines, printingSpy.lines)
+		}
+	})
+
+	t.Run("LoadMessages should fill in the messages left out with English", func(t *testing.T) {
+		messages, _ := LoadMessages(filepath.Join(testDir, "messages.yaml"))
+		if messages.Solution != English.Solution || messages.Quit != "ende" {
+			t.Fatalf("Expected English for the messages left out, got %+v", messages)
+		}
+	})
+
+	t.Run("LoadMessages should reject a message that isn't a valid template", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "bad.json")
+		ioutil.WriteFile(path, []byte(`{"bye": "{{.Nope}}"}`), 0600)
+		_, err := LoadMessages(path)
+		if err == nil || !strings.Contains(err.Error(), "Nope") {
+			t.Fatalf("Expected an error about the unknown field, got %v", err)
+		}
+	})
+}
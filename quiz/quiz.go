@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -34,15 +36,26 @@ func (s *realSleeper) Sleep(d time.Duration) {
 	time.Sleep(d)
 }
 
-var errBadColumns = errors.New("CSV file has a record with the wrong number of columns, expected two")
-var greetingMessage = "Welcome to the maths quiz! Press any button to continue, or enter 'q' at any time to exit"
-var byeMessage = "Thank you for playing. your final score is"
-var timeOutMessage = "You ran out of time. Thank you for playing. Your final score is"
-var outOf = "out of"
-var endGame = "q"
+// defined for mocking and dependency injection
+type clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (c *realClock) Now() time.Time {
+	return time.Now()
+}
+
+var errBadColumns = errors.New("CSV file has a record with the wrong columns, expected question, answer and optional key=value columns")
 
-func parseCSV(reader *csv.Reader, header bool) (map[string]int, error) {
-	parsedCSV := make(map[string]int)
+// parseCSV reads every question/answer record of reader into a Deck,
+// keeping the order of the file. Duplicate questions are kept; use
+// Deck.Duplicates to find them
+func parseCSV(reader *csv.Reader, header bool) (Deck, error) {
+	deck := make(Deck, 0, 10)
+	// records may have any number of key=value columns after the answer
+	reader.FieldsPerRecord = -1
 
 	if header {
 		// skip header
@@ -53,107 +66,461 @@ func parseCSV(reader *csv.Reader, header bool) (map[string]int, error) {
 		if err == io.EOF {
 			break
 		}
+		var csvErr *csv.ParseError
+		if errors.As(err, &csvErr) {
+			return deck, &ParseError{Line: csvErr.Line, Column: csvErr.Column, Err: csvErr.Err}
+		}
 		if err != nil {
-			return parsedCSV, err
+			return deck, err
 		}
-		if len(record) != 2 {
-			return parsedCSV, errBadColumns
+		if len(record) < 2 {
+			line, column := reader.FieldPos(0)
+			return deck, &ParseError{Line: line, Column: column, Record: record, Err: errBadColumns}
 		}
-		errExtract := extractQA(record, parsedCSV)
+		question, errExtract := extractQA(record, reader.FieldPos)
 		if errExtract != nil {
-			return parsedCSV, errExtract
+			return deck, errExtract
+		}
+		deck = append(deck, question)
+	}
+
+	return deck, nil
+}
+
+// extractQA turns a record into a Question. Any columns after the
+// question and answer are key=value pairs: match names the matcher (see
+// ParseMatcher), options lists the options of a multiple-choice question
+// and accept other correct answers, and tags the question's tags, all
+// separated by |. category, points, hint and timer fill in the fields of
+// the same name, and anything else is kept in the question's Meta. A
+// blank answer to an arithmetic question is computed from the question,
+// and any other blank answer is an error. position locates the fields of
+// the record, like csv.Reader.FieldPos, and errors are ParseErrors
+// pointing at the offending field
+func extractQA(record []string, position func(field int) (line, column int)) (Question, error) {
+	fail := func(field int, err error) (Question, error) {
+		line, column := position(field)
+		return Question{}, &ParseError{Line: line, Column: column, Record: record, Err: err}
+	}
+	line, _ := position(0)
+	question := Question{Text: record[0], Answer: strings.TrimSpace(record[1]), Line: line}
+	// matchField is the field naming the matcher, if there's one
+	matchField := 1
+	for i, column := range record[2:] {
+		field := i + 2
+		key, value, found := strings.Cut(column, "=")
+		if !found {
+			return fail(field, errBadColumns)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		switch key {
+		case "match":
+			question.Match = value
+			matchField = field
+		case "options":
+			question.Options = strings.Split(value, "|")
+		case "accept":
+			question.Accept = strings.Split(value, "|")
+		case "category":
+			question.Category = value
+		case "tags":
+			question.Tags = splitTags(value)
+		case "points":
+			points, errPoints := strconv.Atoi(value)
+			if errPoints != nil {
+				return fail(field, errPoints)
+			}
+			question.Points = points
+		case "hint":
+			question.Hint = value
+		case "timer":
+			timer, errTimer := strconv.Atoi(value)
+			if errTimer != nil {
+				return fail(field, errTimer)
+			}
+			question.Timer = timer
+		case "difficulty":
+			difficulty, errDifficulty := strconv.Atoi(value)
+			if errDifficulty != nil {
+				return fail(field, errDifficulty)
+			}
+			question.Difficulty = difficulty
+		default:
+			if question.Meta == nil {
+				question.Meta = make(map[string]string)
+			}
+			question.Meta[key] = value
+		}
+	}
+
+	computeAnswer(&question)
+	if question.Answer == "" {
+		return fail(1, errMissingField)
+	}
+	if strings.TrimSpace(question.Text) == "" {
+		return fail(0, errMissingField)
+	}
+	if errValidate := question.validate(); errValidate != nil {
+		if errors.Is(errValidate, errUnknownMatcher) {
+			return fail(matchField, errValidate)
+		}
+		return fail(1, errValidate)
+	}
+
+	return question, nil
+}
+
+// splitTags splits tags separated by |, trimming the space around them and
+// leaving out empty ones
+func splitTags(tags string) []string {
+	var split []string
+	for _, tag := range strings.Split(tags, "|") {
+		if tag = strings.TrimSpace(tag); len(tag) > 0 {
+			split = append(split, tag)
 		}
 	}
+	return split
+}
 
-	return parsedCSV, nil
+// readLines sends every line of input on the returned channel, closing
+// it once input is exhausted. Rooms read their players' connections
+// through it
+func readLines(input io.Reader) <-chan string {
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(input)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+	return lines
 }
 
-func extractQA(record []string, csvMap map[string]int) error {
-	question, answer := record[0], record[1]
-	intAnswer, errAtoi := strconv.Atoi(strings.TrimSpace(answer))
+// lineReader reads the lines of input one at a time, only once they're
+// asked for, so that waiting for an answer can be cut short by a timer
+// and input past the end of the game is left unread for whoever reads it
+// next. A line asked for when the game ends is the only one read past it
+type lineReader struct {
+	requests chan int
+	lines    chan string
+	stopped  chan int
+	// asked is whether a line was asked for and hasn't been received yet
+	asked bool
+}
 
-	if errAtoi != nil {
-		return strconv.ErrSyntax
+func newLineReader(input io.Reader) *lineReader {
+	r := &lineReader{
+		requests: make(chan int, 1),
+		lines:    make(chan string),
+		stopped:  make(chan int),
 	}
+	go func() {
+		defer close(r.lines)
+		scanner := bufio.NewScanner(byteReader{input})
+		for {
+			select {
+			case <-r.requests:
+			case <-r.stopped:
+				return
+			}
+			if !scanner.Scan() {
+				return
+			}
+			select {
+			case r.lines <- scanner.Text():
+			case <-r.stopped:
+				return
+			}
+		}
+	}()
+	return r
+}
 
-	csvMap[question] = intAnswer
+// next asks for the next line, unless it was asked for already, and
+// returns the channel it comes on, closed once input is exhausted. Whoever
+// receives the line calls received
+func (r *lineReader) next() <-chan string {
+	if !r.asked {
+		r.asked = true
+		r.requests <- 1
+	}
+	return r.lines
+}
 
-	return nil
+// received marks the line asked for as received
+func (r *lineReader) received() {
+	r.asked = false
+}
+
+// stop stops reading input once the line being read, if any, is read
+func (r *lineReader) stop() {
+	close(r.stopped)
+}
+
+// byteReader reads a byte at a time, so that scanning a line doesn't read
+// ahead of it
+type byteReader struct {
+	r io.Reader
+}
+
+func (b byteReader) Read(p []byte) (int, error) {
+	if len(p) > 1 {
+		p = p[:1]
+	}
+	return b.r.Read(p)
+}
+
+// nextAnswer waits for the next line of input, or for timeout, and
+// returns false if timeout came first. Once input is exhausted no
+// more answers are coming, so it's left to timeout, or to the timer
+// of the whole game. Once the reader is stopped it returns false
+func nextAnswer(lines *lineReader, timeout <-chan int) (string, bool) {
+	select {
+	case userInput, ok := <-lines.next():
+		if ok {
+			lines.received()
+			return userInput, true
+		}
+		select {
+		case <-timeout:
+		case <-lines.stopped:
+		}
+		return "", false
+	case <-timeout:
+		return "", false
+	case <-lines.stopped:
+		return "", false
+	}
 }
 
 // gameLoop controls the basic loop of the quiz: Pose question,
-// check answer, update score, and post next question
-func gameLoop(qaMap map[string]int, input io.Reader, output printer, scorePtr *int, done chan int) {
-	scanner := bufio.NewScanner(input)
-	for question, answer := range qaMap {
-		output.Println(question)
-		scanner.Scan()
-		userInput := scanner.Text()
-		if userInput == endGame {
-			done <- 1
+// check answer, update score, and post next question. If the
+// question has a time limit and it runs out, the question is
+// marked as timed out and the loop moves on. After a miss the
+// question's hint is shown, and in practice mode its answer. It's
+// the terminal front-end of a session
+func gameLoop(game *session, lines *lineReader, output printer, sleepy sleeper, done chan Ending) {
+	messages := game.config.messages()
+	for {
+		question, ok := game.question()
+		if !ok {
+			break
+		}
+		if game.config.Countdown && game.limit > 0 {
+			left := game.remaining().Round(time.Second)
+			output.Println(messages.fill(messages.Countdown, MessageData{Time: left}))
+		}
+		output.Println(question.Text)
+		for i, option := range question.Options {
+			output.Println(optionLabel(i), option)
+		}
+		timeout := make(chan int, 1)
+		if limit := game.config.questionTimer(question); limit > 0 {
+			go func() {
+				sleepy.Sleep(limit)
+				timeout <- 1
+			}()
+		}
+		userInput, answered := nextAnswer(lines, timeout)
+		if !answered && game.isOver() {
+			break
+		}
+		if !answered {
+			output.Println(messages.fill(messages.QuestionTimeOut, MessageData{}))
+			game.timeOut()
+			printMiss(question, game.config, messages, output)
+			continue
+		}
+		if question.quits(userInput, messages.Quit) {
+			done <- Quit
 			return
 		}
-		userAnswer, err := strconv.Atoi(strings.TrimSpace(userInput))
-		if err == nil && userAnswer == answer {
-			*scorePtr++
+		answer, recorded := game.answer(userInput)
+		if recorded && !answer.Correct {
+			printMiss(question, game.config, messages, output)
 		}
 	}
-	done <- 1
+	done <- Completed
 	return
 }
 
+// printMiss tells the player about a question they missed: its hint, if
+// it has one, and its answer in practice mode
+func printMiss(question Question, config Config, messages Messages, output printer) {
+	for _, line := range missNotes(question, config, messages) {
+		output.Println(line)
+	}
+}
+
+// missNotes are the lines telling the player about a question they missed
+func missNotes(question Question, config Config, messages Messages) []string {
+	var notes []string
+	if len(question.Hint) > 0 {
+		notes = append(notes, messages.fill(messages.Hint, MessageData{Hint: question.Hint}))
+	}
+	if config.Practice {
+		notes = append(notes, messages.fill(messages.Solution, MessageData{Answer: question.solution()}))
+	}
+	return notes
+}
+
+// printChoices reports the option picked for every multiple-choice
+// question that was answered
+func printChoices(answers []Answer, messages Messages, output printer) {
+	for _, answer := range answers {
+		if answer.Question.isMultipleChoice() {
+			output.Println(messages.fill(messages.Picked, MessageData{Question: answer.Question.Text, Choice: answer.Choice}))
+		}
+	}
+}
+
 // playGame controls the main game: greets, starts the loop, and
 // prints goodbye message. It is private because it's dependency
 // injected. There is a public version PlayGame that has all the
 // injected dependecies filled out and presents a simple public
 // interface
-func playGame(csvPath string, timer int, header bool, input io.Reader, sleepy sleeper, output printer) (int, error) {
-	var score int
-	done := make(chan int)
-	quit := make(chan int)
-	// set up question-answer CSV
-	csvFile, errOpen := os.Open(csvPath)
-	if errOpen != nil {
-		return score, errOpen
-	}
+func playGame(source QuestionSource, config Config, input io.Reader, sleepy sleeper, output printer) (Result, error) {
+	return playInterruptible(source, config, input, sleepy, output, nil, &realClock{})
+}
 
-	// parse question-answer CSV to produce a Map of QA
-	reader := csv.NewReader(csvFile)
-	qaMap, errParse := parseCSV(reader, header)
-	if errParse != nil {
-		return score, errParse
+// playInterruptible is playGame, where a signal on interrupt quits the
+// game just like the player typing the end game keyword. A player who
+// quits before the end gets a snapshot of the game in the result, and a
+// game resumed from a snapshot picks up where it was left
+func playInterruptible(source QuestionSource, config Config, input io.Reader, sleepy sleeper, output printer, interrupt <-chan os.Signal, watch clock) (Result, error) {
+	// both are buffered, for the game loop and the countdown to finish
+	// after the game ends however it ends
+	done := make(chan Ending, 1)
+	quit := make(chan int, 1)
+	// load the questions, in order, from wherever they come from
+	deck, errSource := source.Questions()
+	if errSource != nil {
+		return Result{}, errSource
+	}
+	var game *session
+	if config.Resume == nil {
+		game = newSession(deck, config, watch)
+	} else {
+		var errResume error
+		game, errResume = resumeSession(deck, config)
+		if errResume != nil {
+			return Result{}, errResume
+		}
+		// the options saved with the game take over
+		config = game.config
 	}
-	maxScore := len(qaMap)
+	game.clock = watch
 
 	// greet and wait for user input to start game
-	scanner := bufio.NewScanner(input)
-	output.Println(greetingMessage)
-	scanner.Scan()
-	userInput := scanner.Text()
-	if userInput == endGame {
-		output.Println(byeMessage, score)
-		return score, nil
+	messages := config.messages()
+	lines := newLineReader(input)
+	defer lines.stop()
+	output.Println(messages.fill(messages.Greeting, MessageData{Time: game.limit.Round(time.Second)}))
+	var userInput string
+	select {
+	case userInput = <-lines.next():
+		lines.received()
+	case <-interrupt:
+		userInput = messages.Quit
+	}
+	if userInput == messages.Quit {
+		game.end(Quit)
+		result := game.result()
+		result.Snapshot = game.snapshot()
+		output.Println(messages.fill(messages.Bye, resultData(result)))
+		return result, nil
 	}
 
-	go gameLoop(qaMap, input, output, &score, done)
-	go func() {
-		sleepy.Sleep(time.Duration(timer) * time.Second)
-		quit <- 1
-	}()
+	game.start()
+	go gameLoop(game, lines, output, sleepy, done)
+	// a game without a time limit never runs out of time
+	if game.limit > 0 {
+		go func() {
+			countDown(game, messages, sleepy, output)
+			quit <- 1
+		}()
+	}
 
+	var result Result
 	select {
-	case <-done:
-		output.Println(byeMessage, score, outOf, maxScore)
-		return score, nil
+	case ending := <-done:
+		game.end(ending)
+		result = game.result()
+		output.Println(messages.fill(messages.Bye, resultData(result)))
+	case <-interrupt:
+		game.end(Quit)
+		result = game.result()
+		output.Println(messages.fill(messages.Bye, resultData(result)))
 	case <-quit:
-		output.Println(timeOutMessage, score, outOf, maxScore)
-		return score, nil
+		game.end(OutOfTime)
+		result = game.result()
+		output.Println(messages.fill(messages.TimeOut, resultData(result)))
+	}
+	for _, category := range result.Categories() {
+		data := MessageData{Category: category.Category, Score: category.Score, MaxScore: category.Asked}
+		output.Println(messages.fill(messages.Category, data))
+	}
+	printChoices(result.Answers, messages, output)
+	if config.Practice {
+		output.Println(messages.fill(messages.FirstTry, resultData(result)))
+	}
+	if config.Adaptive {
+		output.Println(messages.fill(messages.Skill, resultData(result)))
+	}
+	if config.random() {
+		output.Println(messages.fill(messages.Seed, resultData(result)))
+	}
+	if result.Ending == Quit && !game.finished() {
+		result.Snapshot = game.snapshot()
+	}
+	return result, nil
+}
+
+// countDown sleeps until the game runs out of time, warning the player
+// at every threshold of the game's Warnings on the way. It stops at the
+// first warning after the game ended
+func countDown(game *session, messages Messages, sleepy sleeper, output printer) {
+	warnings := append([]int(nil), game.config.Warnings...)
+	sort.Sort(sort.Reverse(sort.IntSlice(warnings)))
+	var slept time.Duration
+	for _, warning := range warnings {
+		left := time.Duration(warning) * time.Second
+		if left <= 0 || left >= game.limit-slept {
+			continue
+		}
+		sleepy.Sleep(game.limit - slept - left)
+		slept = game.limit - left
+		if game.isOver() {
+			return
+		}
+		output.Println(messages.fill(messages.Warning, MessageData{Time: left}))
+	}
+	sleepy.Sleep(game.limit - slept)
+}
+
+// resultData is what the messages at the end of the game can refer to
+func resultData(result Result) MessageData {
+	return MessageData{
+		Score:    result.Score,
+		MaxScore: result.MaxScore,
+		Points:   result.Points,
+		FirstTry: result.FirstTry,
+		Skill:    result.Skill,
+		Time:     result.Duration.Round(time.Second),
+		Seed:     result.Seed,
 	}
 }
 
-// PlayGame reads the csv at csvPath for question/answer pairs,
-// skipping the header if there is one, and plays the game
-// for a maximum of timer seconds
-func PlayGame(csvPath string, timer int, header bool) (int, error) {
-	return playGame(csvPath, timer, header, os.Stdin, &realSleeper{}, &realPrinter{})
+// PlayGame gets its question/answer pairs from source, e.g.
+// File for a question bank on disk, and plays the game with
+// the options in config, e.g. for a maximum of config.Timer
+// seconds. The Result details every question asked and how
+// the game ended. Interrupting the program, e.g. with Ctrl-C,
+// quits the game. To play on other input and output, see Game
+func PlayGame(source QuestionSource, config Config) (Result, error) {
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	return NewGame(WithSource(source), WithConfig(config), WithInterrupt(interrupt)).Play()
 }
@@ -3,10 +3,16 @@ package quiz
 import (
 	"bytes"
 	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
 	"os"
 	"path"
 	"reflect"
 	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -17,15 +23,53 @@ type spySleeper struct {
 	args []time.Duration
 }
 
+// the game loop and the game's timer both print, so printers are
+// guarded by a mutex
 type spyPrinter struct {
+	mu     sync.Mutex
 	called int
 }
 
 func (s *spyPrinter) Println(a ...interface{}) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.called++
 	return 1, nil
 }
 
+// recordingPrinter keeps every printed line, for tests that care about
+// what was printed and in which order
+type recordingPrinter struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (r *recordingPrinter) Println(a ...interface{}) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	line := fmt.Sprintln(a...)
+	r.lines = append(r.lines, strings.TrimSuffix(line, "\n"))
+	return len(line), nil
+}
+
+// expiringSleeper returns straight away from sleeps of expire, as if that
+// much time had passed, and never returns from any other sleep
+type expiringSleeper struct {
+	expire time.Duration
+}
+
+func (e *expiringSleeper) Sleep(d time.Duration) {
+	if d != e.expire {
+		select {}
+	}
+}
+
+type matcherFunc func(answer, input string) bool
+
+func (m matcherFunc) Match(answer, input string) bool {
+	return m(answer, input)
+}
+
 func (s *spySleeper) Sleep(d time.Duration) {
 	s.args = append(s.args, d)
 }
@@ -33,25 +77,96 @@ func (s *spySleeper) Sleep(d time.Duration) {
 func TestParseCSV(t *testing.T) {
 	t.Run("CSV with more than two columns should be gracefully rejected", func(t *testing.T) {
 		_, errParse := setupParseCSV("3_column.csv", false)
-		if errParse != errBadColumns {
+		if !errors.Is(errParse, errBadColumns) {
 			t.Fatalf("Expected error %v, got error %v", errBadColumns, errParse)
 		}
 	})
 
 	t.Run("CSV with fewer than two columns should be gracefully rejected", func(t *testing.T) {
 		_, errParse := setupParseCSV("1_column.csv", false)
-		if errParse != errBadColumns {
+		if !errors.Is(errParse, errBadColumns) {
 			t.Fatalf("Expected error %v, got error %v", errBadColumns, errParse)
 		}
 	})
 
-	t.Run("CSV with non-integer answers should be gracefully rejected", func(t *testing.T) {
-		_, errParse := setupParseCSV("non_int.csv", false)
-		if errParse != strconv.ErrSyntax {
+	t.Run("CSV with non-integer answers should be accepted", func(t *testing.T) {
+		deck, errParse := setupParseCSV("non_int.csv", false)
+		if errParse != nil {
+			t.Fatalf("Expected no error, got %v", errParse)
+		}
+		if !deck[1].Check("4.5") {
+			t.Fatalf("Expected 4.5 to be a correct answer to %q", deck[1].Text)
+		}
+	})
+
+	t.Run("CSV with non-integer answers to int questions should be gracefully rejected", func(t *testing.T) {
+		_, errParse := setupParseCSV("bad_int.csv", false)
+		if !errors.Is(errParse, strconv.ErrSyntax) {
 			t.Fatalf("Expected error %v, got error %v", strconv.ErrSyntax, errParse)
 		}
 	})
 
+	t.Run("CSV with an unknown matcher should be gracefully rejected", func(t *testing.T) {
+		_, errParse := setupParseCSV("bad_matcher.csv", false)
+		if !errors.Is(errParse, errUnknownMatcher) {
+			t.Fatalf("Expected error %v, got error %v", errUnknownMatcher, errParse)
+		}
+	})
+
+	t.Run("CSV with a multiple-choice question should be parsed", func(t *testing.T) {
+		deck, errParse := setupParseCSV("choice.csv", false)
+		if errParse != nil {
+			t.Fatalf("Expected no error, got %v", errParse)
+		}
+		expectedOptions := []string{"London", "Paris", "Rome"}
+		if !reflect.DeepEqual(deck[0].Options, expectedOptions) {
+			t.Fatalf("Expected options %v, got %v", expectedOptions, deck[0].Options)
+		}
+	})
+
+	t.Run("CSV with a multiple-choice answer that isn't an option should be gracefully rejected", func(t *testing.T) {
+		_, errParse := setupParseCSV("bad_choice.csv", false)
+		if !errors.Is(errParse, errBadOptions) {
+			t.Fatalf("Expected error %v, got error %v", errBadOptions, errParse)
+		}
+	})
+
+	t.Run("CSV with a blank answer should be gracefully rejected", func(t *testing.T) {
+		_, errParse := CSVSource{Reader: strings.NewReader("1+1,2\nCapital of France?,\n")}.Questions()
+		var parseErr *ParseError
+		if !errors.As(errParse, &parseErr) || !errors.Is(errParse, errMissingField) {
+			t.Fatalf("Expected a ParseError for the missing answer, got %v", errParse)
+		}
+		if parseErr.Line != 2 || parseErr.Column != 20 {
+			t.Fatalf("Expected the error at 2:20, got %v", parseErr)
+		}
+	})
+
+	t.Run("CSV tags should be trimmed", func(t *testing.T) {
+		deck, errParse := CSVSource{Reader: strings.NewReader("1+1,2,tags=easy | sums|\n")}.Questions()
+		if errParse != nil || !reflect.DeepEqual(deck[0].Tags, []string{"easy", "sums"}) {
+			t.Fatalf("Expected the tags [easy sums], got %+v and %v", deck, errParse)
+		}
+	})
+
+	t.Run("Parse errors should tell where the problem is and why", func(t *testing.T) {
+		_, errParse := File(path.Join(testDir, "bad_points.csv"), false).Questions()
+		var parseErr *ParseError
+		if !errors.As(errParse, &parseErr) {
+			t.Fatalf("Expected a ParseError, got %v", errParse)
+		}
+		if parseErr.File != path.Join(testDir, "bad_points.csv") || parseErr.Line != 2 || parseErr.Column != 7 {
+			t.Fatalf("Expected the error at bad_points.csv:2:7, got %v", parseErr)
+		}
+		if !reflect.DeepEqual(parseErr.Record, []string{"1+1", "2", "points=two"}) {
+			t.Fatalf("Expected the offending record, got %v", parseErr.Record)
+		}
+		var numErr *strconv.NumError
+		if !errors.As(errParse, &numErr) || numErr.Num != "two" {
+			t.Fatalf("Expected the strconv error to be kept, got %v", errParse)
+		}
+	})
+
 	t.Run("CSV with header should be accepted", func(t *testing.T) {
 		_, errParse := setupParseCSV("header.csv", true)
 		if errParse != nil {
@@ -60,34 +175,90 @@ func TestParseCSV(t *testing.T) {
 	})
 
 	t.Run("Acceptable CSV should be correctly parsed", func(t *testing.T) {
-		qaMap, err := setupParseCSV("correct.csv", false)
+		deck, err := setupParseCSV("correct.csv", false)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		expectedDeck := Deck{
+			{Text: "2+5", Answer: "7", Line: 1},
+			{Text: "What does 3+9 equal, sir?", Answer: "12", Line: 2},
+		}
+
+		if !reflect.DeepEqual(deck, expectedDeck) {
+			t.Fatalf("Expected parsed CSV to be %v, got %v", expectedDeck, deck)
+		}
+	})
+
+	t.Run("CSV with duplicate questions should keep them in order and report them", func(t *testing.T) {
+		deck, err := setupParseCSV("duplicates.csv", false)
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
 		}
-		expectedMap := map[string]int{
-			"2+5":                       7,
-			"What does 3+9 equal, sir?": 12,
+		if len(deck) != 3 {
+			t.Fatalf("Expected all 3 questions to be kept, got %d", len(deck))
 		}
+		expectedDuplicates := map[string][]int{"1+1": {1, 3}}
+		if duplicates := deck.Duplicates(); !reflect.DeepEqual(duplicates, expectedDuplicates) {
+			t.Fatalf("Expected duplicates %v, got %v", expectedDuplicates, duplicates)
+		}
+	})
+}
+
+func TestMatchers(t *testing.T) {
+	cases := []struct {
+		match  string
+		answer string
+		input  string
+		want   bool
+	}{
+		{"", "12", " 12 ", true},
+		{"", "12", "12.0", false},
+		{"", "4.5", "4.50", true},
+		{"", "Paris", "  paris ", true},
+		{"", "New York", "new   york", true},
+		{"", "café", "cafe\u0301", true},
+		{"", "Paris", "Rome", false},
+		{"", "NaN", "nan", true},
+		{"", "Infinity", "INFINITY", true},
+		{"", "0x10", "16", false},
+		{"int", "12", "twelve", false},
+		{"float:0.01", "3.14159", "3.14", true},
+		{"float:0.001", "3.14159", "3.14", false},
+		{"text", "Ünïcode", "üNÏCODE", true},
+		{"exact", "Paris", "paris", false},
+		{"exact", "Paris", "Paris", true},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%q matching %q against %q", c.match, c.answer, c.input), func(t *testing.T) {
+			question := Question{Answer: c.answer, Match: c.match}
+			if got := question.Check(c.input); got != c.want {
+				t.Fatalf("Expected %v, got %v", c.want, got)
+			}
+		})
+	}
 
-		if !reflect.DeepEqual(qaMap, expectedMap) {
-			t.Fatalf("Expected parsed CSV to be %v, got %v", expectedMap, qaMap)
+	t.Run("A custom Matcher should take precedence over Match", func(t *testing.T) {
+		anything := matcherFunc(func(answer, input string) bool { return true })
+		question := Question{Answer: "42", Match: "int", Matcher: anything}
+		if !question.Check("not even close") {
+			t.Fatalf("Expected the custom matcher to accept any input")
 		}
 	})
 }
 
 func TestPlayGame(t *testing.T) {
 	t.Run("Basic game loop of pose question then accept answer then update score then pose next question, should work", func(t *testing.T) {
-		qaMap := map[string]int{
-			"1+4":  5,
-			"10/5": 2,
-			"5*6":  30,
+		deck := Deck{
+			{Text: "1+4", Answer: "5"},
+			{Text: "10/5", Answer: "2"},
+			{Text: "5*6", Answer: "30"},
 		}
-		var score int
-		done := make(chan int, 1)
+		game := newSession(deck, Config{}, &realClock{})
+		done := make(chan Ending, 1)
 		outSpy := &spyPrinter{}
 		// real := realPrinter{}
 		userResponse := bytes.NewBufferString("5\n3\nq\n")
-		gameLoop(qaMap, userResponse, outSpy, &score, done)
+		gameLoop(game, newLineReader(userResponse), outSpy, &spySleeper{}, done)
 
 		expectedResponses := 3
 
@@ -98,11 +269,132 @@ func TestPlayGame(t *testing.T) {
 		}
 	})
 
+	t.Run("Questions should be asked in deck order", func(t *testing.T) {
+		deck := Deck{
+			{Text: "1+4", Answer: "5"},
+			{Text: "10/5", Answer: "2"},
+			{Text: "5*6", Answer: "30"},
+		}
+		game := newSession(deck, Config{}, &realClock{})
+		done := make(chan Ending, 1)
+		outSpy := &recordingPrinter{}
+		userResponse := bytes.NewBufferString("5\n2\n1\n")
+		gameLoop(game, newLineReader(userResponse), outSpy, &spySleeper{}, done)
+
+		expectedLines := []string{"1+4", "10/5", "5*6"}
+		if !reflect.DeepEqual(outSpy.lines, expectedLines) {
+			t.Fatalf("Expected questions %v, got %v", expectedLines, outSpy.lines)
+		}
+		if score := game.score(); score != 2 {
+			t.Fatalf("Expected score 2, got %d", score)
+		}
+	})
+
+	t.Run("Text answers should be compared through the question's matcher", func(t *testing.T) {
+		deck := Deck{
+			{Text: "Capital of France?", Answer: "Paris"},
+			{Text: "Capital of Italy?", Answer: "Rome", Match: "exact"},
+		}
+		game := newSession(deck, Config{}, &realClock{})
+		done := make(chan Ending, 1)
+		userResponse := bytes.NewBufferString(" paris\nrome\n")
+		gameLoop(game, newLineReader(userResponse), &spyPrinter{}, &spySleeper{}, done)
+		if score := game.score(); score != 1 {
+			t.Fatalf("Expected score 1, got %d", score)
+		}
+	})
+
+	t.Run("Multiple-choice questions should print lettered options and record the picked one", func(t *testing.T) {
+		deck := Deck{
+			{Text: "Capital of France?", Answer: "Paris", Options: []string{"London", "Paris", "Rome"}},
+			{Text: "Capital of Italy?", Answer: "Rome", Options: []string{"Rome", "Milan"}},
+		}
+		game := newSession(deck, Config{}, &realClock{})
+		done := make(chan Ending, 1)
+		outSpy := &recordingPrinter{}
+		userResponse := bytes.NewBufferString("B\nb\n")
+		gameLoop(game, newLineReader(userResponse), outSpy, &spySleeper{}, done)
+
+		expectedLines := []string{"Capital of France?", "a) London", "b) Paris", "c) Rome", "Capital of Italy?", "a) Rome", "b) Milan"}
+		if !reflect.DeepEqual(outSpy.lines, expectedLines) {
+			t.Fatalf("Expected lines %v, got %v", expectedLines, outSpy.lines)
+		}
+		answers := game.answered()
+		if !answers[0].Correct || answers[0].Choice != "Paris" {
+			t.Fatalf("Expected a correct pick of Paris, got %+v", answers[0])
+		}
+		if answers[1].Correct || answers[1].Choice != "Milan" {
+			t.Fatalf("Expected an incorrect pick of Milan, got %+v", answers[1])
+		}
+	})
+
+	t.Run("The quit keyword should pick the option it letters", func(t *testing.T) {
+		options := make([]string, 17)
+		for i := range options {
+			options[i] = fmt.Sprint(i)
+		}
+		deck := Deck{
+			{Text: "Pick the last", Answer: "16", Options: options},
+			{Text: "1+4", Answer: "5"},
+		}
+		game := newSession(deck, Config{}, &realClock{})
+		done := make(chan Ending, 1)
+		gameLoop(game, newLineReader(bytes.NewBufferString("q\nq\n")), &spyPrinter{}, &spySleeper{}, done)
+		if ending := <-done; ending != Quit {
+			t.Fatalf("Expected the game to be quit on the second question, got %v", ending)
+		}
+		answers := game.answered()
+		if len(answers) != 1 || !answers[0].Correct {
+			t.Fatalf("Expected q to pick the right option, got %+v", answers)
+		}
+	})
+
+	t.Run("Unanswered questions should time out and the game should move on", func(t *testing.T) {
+		deck := Deck{
+			{Text: "1+4", Answer: "5"},
+			{Text: "10/5", Answer: "2", Timer: 3},
+		}
+		game := newSession(deck, Config{QuestionTimer: 5}, &realClock{})
+		done := make(chan Ending, 1)
+		outSpy := &recordingPrinter{}
+		sleepySpy := &spySleeper{}
+		// the player never answers
+		input, _ := io.Pipe()
+		gameLoop(game, newLineReader(input), outSpy, sleepySpy, done)
+
+		answers := game.answered()
+		if len(answers) != 2 || !answers[0].TimedOut || !answers[1].TimedOut {
+			t.Fatalf("Expected both questions to time out, got %+v", answers)
+		}
+		expectedSleeps := []time.Duration{5 * time.Second, 3 * time.Second}
+		if !reflect.DeepEqual(sleepySpy.args, expectedSleeps) {
+			t.Fatalf("Expected time limits %v, got %v", expectedSleeps, sleepySpy.args)
+		}
+		expectedLines := []string{"1+4", English.QuestionTimeOut, "10/5", English.QuestionTimeOut}
+		if !reflect.DeepEqual(outSpy.lines, expectedLines) {
+			t.Fatalf("Expected lines %v, got %v", expectedLines, outSpy.lines)
+		}
+	})
+
+	t.Run("The global timer should still end the game while questions have time left", func(t *testing.T) {
+		printingSpy := &recordingPrinter{}
+		// the player starts the game and then never answers
+		input, player := io.Pipe()
+		go io.WriteString(player, "\n")
+		deck := Deck{{Text: "1+4", Answer: "5"}}
+		config := Config{Timer: 30, QuestionTimer: 60}
+		playGame(deck, config, input, &expiringSleeper{expire: 30 * time.Second}, printingSpy)
+		last := printingSpy.lines[len(printingSpy.lines)-1]
+		if !strings.HasPrefix(last, "You ran out of time.") {
+			t.Fatalf("Expected the game to time out, got %q", last)
+		}
+	})
+
 	t.Run("Game should exit after timer has run out and show user final score", func(t *testing.T) {
 		sleepySpy := &spySleeper{args: make([]time.Duration, 0, 5)}
 		printingSpy := &spyPrinter{}
 		userResponse := bytes.NewBufferString("\n")
-		playGame(path.Join(testDir, "correct.csv"), 30, false, userResponse, sleepySpy, printingSpy)
+		playGame(CSVFile(path.Join(testDir, "correct.csv"), false), Config{Timer: 30}, userResponse, sleepySpy, printingSpy)
 		expectedSleep := time.Duration(30) * time.Second
 		if len(sleepySpy.args) != 1 || sleepySpy.args[0] != expectedSleep {
 			t.Fatalf("time.Sleep got called with args %v", sleepySpy.args)
@@ -113,11 +405,303 @@ func TestPlayGame(t *testing.T) {
 	})
 }
 
-func setupParseCSV(filename string, header bool) (map[string]int, error) {
+func TestPlayGameResult(t *testing.T) {
+	deck := Deck{
+		{Text: "1+4", Answer: "5"},
+		{Text: "Capital of France?", Answer: "Paris"},
+	}
+	// the global timer never runs out, unless the test says so
+	noTimeout := &expiringSleeper{}
+
+	t.Run("A completed game should report every answer", func(t *testing.T) {
+		result, err := playGame(deck, Config{Timer: 30}, strings.NewReader("\n5\nRome\n"), noTimeout, &spyPrinter{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.Ending != Completed || result.Score != 1 || result.MaxScore != 2 {
+			t.Fatalf("Expected a completed game scoring 1 out of 2, got %+v", result)
+		}
+		if len(result.Answers) != 2 || result.Answers[1].Input != "Rome" || result.Answers[1].Correct {
+			t.Fatalf("Expected the raw, incorrect input Rome to be recorded, got %+v", result.Answers)
+		}
+	})
+
+	t.Run("A game the player quits should say so", func(t *testing.T) {
+		result, _ := playGame(deck, Config{Timer: 30}, strings.NewReader("\n5\nq\n"), noTimeout, &spyPrinter{})
+		if result.Ending != Quit || result.Score != 1 || len(result.Answers) != 1 {
+			t.Fatalf("Expected a quit game with one correct answer, got %+v", result)
+		}
+		if len(result.Unanswered) != 1 || result.Unanswered[0].Text != "Capital of France?" {
+			t.Fatalf("Expected the question left to be unanswered, got %+v", result.Unanswered)
+		}
+	})
+
+	t.Run("The score should be broken down by category, whatever its case", func(t *testing.T) {
+		categorized := Deck{
+			{Text: "1+4", Answer: "5", Category: "maths"},
+			{Text: "Capital of France?", Answer: "Paris", Category: "geography"},
+			{Text: "2+2", Answer: "4", Category: "Maths"},
+			{Text: "Who painted the Mona Lisa?", Answer: "Leonardo"},
+		}
+		printingSpy := &recordingPrinter{}
+		result, _ := playGame(categorized, Config{Timer: 30}, strings.NewReader("\n5\nRome\n4\nRaphael\n"), noTimeout, printingSpy)
+		expected := []CategoryScore{{"maths", 2, 2}, {"geography", 0, 1}}
+		if !reflect.DeepEqual(result.Categories(), expected) {
+			t.Fatalf("Expected categories %v, got %v", expected, result.Categories())
+		}
+		expectedLines := []string{"maths: 2 out of 2", "geography: 0 out of 1"}
+		if lines := printingSpy.lines[len(printingSpy.lines)-2:]; !reflect.DeepEqual(lines, expectedLines) {
+			t.Fatalf("Expected lines %v, got %v", expectedLines, lines)
+		}
+	})
+
+	t.Run("A game that runs out of time should say so", func(t *testing.T) {
+		input, player := io.Pipe()
+		go io.WriteString(player, "\n")
+		result, _ := playGame(deck, Config{Timer: 30}, input, &expiringSleeper{expire: 30 * time.Second}, &spyPrinter{})
+		if result.Ending != OutOfTime || len(result.Answers) != 0 {
+			t.Fatalf("Expected a game out of time with no answers, got %+v", result)
+		}
+	})
+
+	t.Run("Practice mode should show the answer to a miss and ask it again", func(t *testing.T) {
+		printingSpy := &recordingPrinter{}
+		config := Config{Timer: 30, Practice: true, Retries: 2}
+		result, _ := playGame(deck, config, strings.NewReader("\n3\nParis\n5\n"), noTimeout, printingSpy)
+		expectedLines := []string{"Welcome to the maths quiz! Press any button to continue, or enter 'q' at any time to exit", "1+4", "Not quite, the answer is 5", "Capital of France?", "1+4"}
+		if !reflect.DeepEqual(printingSpy.lines[:5], expectedLines) {
+			t.Fatalf("Expected lines %v, got %v", expectedLines, printingSpy.lines)
+		}
+		if result.Score != 2 || result.FirstTry != 1 || result.MaxScore != 2 {
+			t.Fatalf("Expected 2 correct out of 2 with 1 on the first try, got %+v", result)
+		}
+		attempts := []int{result.Answers[0].Attempt, result.Answers[1].Attempt, result.Answers[2].Attempt}
+		if !reflect.DeepEqual(attempts, []int{1, 1, 2}) {
+			t.Fatalf("Expected attempts [1 1 2], got %v", attempts)
+		}
+	})
+
+	t.Run("The hint of a question should be shown after a miss", func(t *testing.T) {
+		printingSpy := &recordingPrinter{}
+		hinted := Deck{{Text: "Capital of France?", Answer: "Paris", Hint: "the city of light"}}
+		playGame(hinted, Config{Timer: 30}, strings.NewReader("\nRome\n"), noTimeout, printingSpy)
+		expectedLines := []string{"Capital of France?", "Hint: the city of light"}
+		if !reflect.DeepEqual(printingSpy.lines[1:3], expectedLines) {
+			t.Fatalf("Expected lines %v, got %v", expectedLines, printingSpy.lines)
+		}
+	})
+
+	t.Run("Practice mode should stop asking a question after its retries", func(t *testing.T) {
+		config := Config{Timer: 30, Practice: true, Retries: 1}
+		result, _ := playGame(deck[:1], config, strings.NewReader("\n1\n2\n"), noTimeout, &spyPrinter{})
+		if result.Ending != Completed || len(result.Answers) != 2 || result.Score != 0 {
+			t.Fatalf("Expected a completed game with two missed attempts, got %+v", result)
+		}
+	})
+}
+
+func TestQuestionSource(t *testing.T) {
+	expectedDeck := Deck{
+		{Text: "2+5", Answer: "7", Line: 1},
+		{Text: "What does 3+9 equal, sir?", Answer: "12", Line: 2},
+	}
+	sources := map[string]QuestionSource{
+		"CSVFile":   CSVFile(path.Join(testDir, "correct.csv"), false),
+		"CSVSource": CSVSource{Reader: strings.NewReader("2+5,7\n\"What does 3+9 equal, sir?\", 12\n")},
+		"FSSource":  FSSource{FS: os.DirFS(testDir), Name: "header.csv", Header: true},
+		"Deck":      expectedDeck,
+	}
+	for name, source := range sources {
+		t.Run(name+" should produce a deck of questions", func(t *testing.T) {
+			deck, err := source.Questions()
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if name == "FSSource" {
+				if len(deck) != 2 || deck[0].Line != 2 {
+					t.Fatalf("Expected 2 questions starting after the header, got %v", deck)
+				}
+				return
+			}
+			if !reflect.DeepEqual(deck, expectedDeck) {
+				t.Fatalf("Expected deck %v, got %v", expectedDeck, deck)
+			}
+		})
+	}
+
+	t.Run("Errors from a SourceFunc should stop the game before it starts", func(t *testing.T) {
+		errGenerator := errors.New("generator broke")
+		source := SourceFunc(func() (Deck, error) { return nil, errGenerator })
+		printingSpy := &spyPrinter{}
+		_, err := playGame(source, Config{Timer: 30}, bytes.NewBufferString("\n"), &spySleeper{}, printingSpy)
+		if err != errGenerator {
+			t.Fatalf("Expected error %v, got %v", errGenerator, err)
+		}
+		if printingSpy.called != 0 {
+			t.Fatalf("Expected nothing to be printed, got %d lines", printingSpy.called)
+		}
+	})
+}
+
+func TestShuffleOptions(t *testing.T) {
+	t.Run("Shuffling should reorder options without touching the original deck", func(t *testing.T) {
+		options := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+		deck := Deck{{Text: "Pick a", Answer: "a", Options: options}, {Text: "1+1", Answer: "2"}}
+		shuffled := deck.ShuffleOptions(rand.New(rand.NewSource(1)))
+
+		if !reflect.DeepEqual(deck[0].Options, []string{"a", "b", "c", "d", "e", "f", "g", "h"}) {
+			t.Fatalf("Expected the original deck to be untouched, got %v", deck[0].Options)
+		}
+		if reflect.DeepEqual(shuffled[0].Options, options) {
+			t.Fatalf("Expected options to be shuffled, got %v", shuffled[0].Options)
+		}
+		sameSeed := deck.ShuffleOptions(rand.New(rand.NewSource(1)))
+		if !reflect.DeepEqual(shuffled, sameSeed) {
+			t.Fatalf("Expected the same seed to shuffle the same way, got %v and %v", shuffled, sameSeed)
+		}
+	})
+}
+
+func TestQuestionBanks(t *testing.T) {
+	expectedDeck := Deck{
+		{Text: "What does 3+9 equal?", Answer: "12", Category: "maths", Points: 1},
+		{
+			Text:     "What is the capital of France?",
+			Answer:   "Paris",
+			Accept:   []string{"Paname"},
+			Category: "geography",
+			Tags:     []string{"europe", "capitals"},
+			Points:   2,
+			Hint:     "It's also called the city of light",
+		},
+	}
+	for _, filename := range []string{"bank.json", "bank.yaml"} {
+		t.Run(filename+" should be detected from its extension and parsed", func(t *testing.T) {
+			deck, err := File(path.Join(testDir, filename), false).Questions()
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(deck, expectedDeck) {
+				t.Fatalf("Expected deck %+v, got %+v", expectedDeck, deck)
+			}
+			if !deck[1].Check("paname") {
+				t.Fatalf("Expected an accepted answer to be correct")
+			}
+		})
+	}
+
+	t.Run("Entries without an answer should be gracefully rejected", func(t *testing.T) {
+		file := path.Join(testDir, "missing_answer.yaml")
+		_, err := File(file, false).Questions()
+		var parseErr *ParseError
+		if !errors.Is(err, errMissingField) || !errors.As(err, &parseErr) {
+			t.Fatalf("Expected a ParseError for the missing field, got %v", err)
+		}
+		if parseErr.File != file || parseErr.Line != 1 || parseErr.Column != 0 {
+			t.Fatalf("Expected the error in the first entry of %s, got %v", file, parseErr)
+		}
+	})
+
+	t.Run("CSV columns should fill in the same fields", func(t *testing.T) {
+		deck, err := CSVSource{Reader: strings.NewReader("Capital of France?,Paris,accept=Paname|Lutece,category=geography,tags=europe|capitals,points=2,hint=Light\n")}.Questions()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		expected := Question{
+			Text:     "Capital of France?",
+			Answer:   "Paris",
+			Accept:   []string{"Paname", "Lutece"},
+			Category: "geography",
+			Tags:     []string{"europe", "capitals"},
+			Points:   2,
+			Hint:     "Light",
+			Line:     1,
+		}
+		if !reflect.DeepEqual(deck[0], expected) {
+			t.Fatalf("Expected question %+v, got %+v", expected, deck[0])
+		}
+	})
+}
+
+func TestArrange(t *testing.T) {
+	deck := make(Deck, 20)
+	for i := range deck {
+		deck[i] = Question{Text: strconv.Itoa(i), Answer: strconv.Itoa(i)}
+	}
+
+	t.Run("The same seed should shuffle the deck the same way", func(t *testing.T) {
+		config := Config{Shuffle: true, Seed: 42}
+		first, seed := config.arrange(deck, &realClock{})
+		second, _ := config.arrange(deck, &realClock{})
+		if seed != 42 {
+			t.Fatalf("Expected seed 42 to be used, got %d", seed)
+		}
+		if !reflect.DeepEqual(first, second) {
+			t.Fatalf("Expected the same order twice, got %v and %v", first, second)
+		}
+		if reflect.DeepEqual(first, deck) {
+			t.Fatalf("Expected the deck to be shuffled")
+		}
+	})
+
+	t.Run("Without a seed the clock should seed the shuffle", func(t *testing.T) {
+		watch := &fakeClock{now: time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)}
+		first, seed := Config{Shuffle: true}.arrange(deck, watch)
+		second, _ := Config{Shuffle: true}.arrange(deck, watch)
+		if seed != watch.now.UnixNano() {
+			t.Fatalf("Expected the time on the clock as the seed, got %d", seed)
+		}
+		if !reflect.DeepEqual(first, second) {
+			t.Fatalf("Expected the same order at the same time, got %v and %v", first, second)
+		}
+	})
+
+	t.Run("Sampling should draw Count questions in deck order", func(t *testing.T) {
+		sample, _ := Config{Count: 5, Seed: 7}.arrange(deck, &realClock{})
+		if len(sample) != 5 {
+			t.Fatalf("Expected 5 questions, got %d", len(sample))
+		}
+		for i := 1; i < len(sample); i++ {
+			previous, _ := strconv.Atoi(sample[i-1].Text)
+			current, _ := strconv.Atoi(sample[i].Text)
+			if previous >= current {
+				t.Fatalf("Expected the sample to keep deck order, got %v", sample)
+			}
+		}
+	})
+
+	t.Run("Filters should keep the questions of the category or with any of the tags", func(t *testing.T) {
+		tagged := Deck{
+			{Text: "Capital of France?", Category: "geography", Tags: []string{"europe", "capitals"}},
+			{Text: "1+1", Category: "maths"},
+			{Text: "Capital of Japan?", Category: "Geography", Tags: []string{"asia", "capitals"}},
+			{Text: "Longest river?", Category: "geography", Tags: []string{"africa"}},
+		}
+		filtered, _ := Config{Category: "geography", Tags: []string{"Capitals", "oceania"}}.arrange(tagged, &realClock{})
+		if !reflect.DeepEqual(filtered, Deck{tagged[0], tagged[2]}) {
+			t.Fatalf("Expected the tagged capitals, got %v", filtered)
+		}
+		if all, _ := (Config{}).arrange(tagged, &realClock{}); len(all) != 4 {
+			t.Fatalf("Expected no filter to keep every question, got %v", all)
+		}
+	})
+
+	t.Run("A seed should be picked and reported when none is given", func(t *testing.T) {
+		printingSpy := &recordingPrinter{}
+		config := Config{Timer: 30, Shuffle: true}
+		playGame(deck, config, bytes.NewBufferString("\n"), &spySleeper{}, printingSpy)
+		last := printingSpy.lines[len(printingSpy.lines)-1]
+		if !strings.HasPrefix(last, "To replay this session, use the seed") || strings.HasSuffix(last, " 0") {
+			t.Fatalf("Expected the seed to be reported last, got %q", last)
+		}
+	})
+}
+
+func setupParseCSV(filename string, header bool) (Deck, error) {
 	csvPath := path.Join(testDir, filename)
 	csvFile, errOpen := os.Open(csvPath)
 	if errOpen != nil {
-		return map[string]int{}, errOpen
+		return Deck{}, errOpen
 	}
 	reader := csv.NewReader(csvFile)
 
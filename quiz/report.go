@@ -0,0 +1,230 @@
+package quiz
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var errUnknownFormat = errors.New("unknown report format, expected json, csv or junit")
+
+// reportAnswer is how an answer is written in reports
+type reportAnswer struct {
+	Question string  `json:"question"`
+	Answer   string  `json:"answer"`
+	Category string  `json:"category,omitempty"`
+	Line     int     `json:"line,omitempty"`
+	Input    string  `json:"input"`
+	Choice   string  `json:"choice,omitempty"`
+	Correct  bool    `json:"correct"`
+	TimedOut bool    `json:"timed_out"`
+	Seconds  float64 `json:"seconds"`
+	Attempt  int     `json:"attempt"`
+}
+
+func toReportAnswer(answer Answer) reportAnswer {
+	return reportAnswer{
+		Question: answer.Question.Text,
+		Answer:   answer.Question.Answer,
+		Category: answer.Question.Category,
+		Line:     answer.Question.Line,
+		Input:    answer.Input,
+		Choice:   answer.Choice,
+		Correct:  answer.Correct,
+		TimedOut: answer.TimedOut,
+		Seconds:  answer.Duration.Seconds(),
+		Attempt:  answer.Attempt,
+	}
+}
+
+// CheckReportFormat returns an error unless WriteReport writes reports in
+// format, so that it can be checked before the game
+func CheckReportFormat(format string) error {
+	switch format {
+	case "json", "csv", "junit":
+		return nil
+	default:
+		return errUnknownFormat
+	}
+}
+
+// ReportFormat guesses the format of a report from the extension of its
+// path: .csv for CSV, .xml for JUnit XML, and JSON for anything else
+func ReportFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return "csv"
+	case ".xml":
+		return "junit"
+	default:
+		return "json"
+	}
+}
+
+// WriteReport writes result to w in format, one of
+//
+//	json    the whole result as a JSON object
+//	csv     one record per answer, with a header
+//	junit   a JUnit XML test suite with one test case per answer, failing
+//	        for incorrect answers, and a skipped one per question left
+//	        unanswered, for CI dashboards
+func WriteReport(w io.Writer, result Result, format string) error {
+	switch format {
+	case "json":
+		return writeJSONReport(w, result)
+	case "csv":
+		return writeCSVReport(w, result)
+	case "junit":
+		return writeJUnitReport(w, result)
+	default:
+		return errUnknownFormat
+	}
+}
+
+func writeJSONReport(w io.Writer, result Result) error {
+	report := struct {
+		Score    int            `json:"score"`
+		MaxScore int            `json:"max_score"`
+		FirstTry int            `json:"first_try"`
+		Points   float64        `json:"points"`
+		Skill    float64        `json:"skill,omitempty"`
+		Ending   string         `json:"ending"`
+		Seed     int64          `json:"seed"`
+		Seconds  float64        `json:"seconds"`
+		Answers  []reportAnswer `json:"answers"`
+	}{
+		Score:    result.Score,
+		MaxScore: result.MaxScore,
+		FirstTry: result.FirstTry,
+		Points:   result.Points,
+		Skill:    result.Skill,
+		Ending:   result.Ending.String(),
+		Seed:     result.Seed,
+		Seconds:  result.Duration.Seconds(),
+		Answers:  make([]reportAnswer, 0, len(result.Answers)),
+	}
+	for _, answer := range result.Answers {
+		report.Answers = append(report.Answers, toReportAnswer(answer))
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(report)
+}
+
+func writeCSVReport(w io.Writer, result Result) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"question", "answer", "category", "line", "input", "choice", "correct", "timed_out", "seconds", "attempt"})
+	for _, answer := range result.Answers {
+		a := toReportAnswer(answer)
+		writer.Write([]string{
+			a.Question,
+			a.Answer,
+			a.Category,
+			strconv.Itoa(a.Line),
+			a.Input,
+			a.Choice,
+			strconv.FormatBool(a.Correct),
+			strconv.FormatBool(a.TimedOut),
+			strconv.FormatFloat(a.Seconds, 'f', 3, 64),
+			strconv.Itoa(a.Attempt),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+type junitSuite struct {
+	XMLName  xml.Name    `xml:"testsuite"`
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// skippedCase is the test case of a question the game ended before
+func skippedCase(name, category string) junitCase {
+	testCase := junitCase{Name: name, Classname: "quiz", Time: "0.000"}
+	if category != "" {
+		testCase.Classname += "." + category
+	}
+	testCase.Skipped = &junitSkipped{Message: "not answered, the game ended first"}
+	return testCase
+}
+
+func writeJUnitReport(w io.Writer, result Result) error {
+	suite := junitSuite{
+		Name: "quiz",
+		Time: fmt.Sprintf("%.3f", result.Duration.Seconds()),
+	}
+	// asked counts the questions answered, retries left out
+	asked := 0
+	for _, answer := range result.Answers {
+		if answer.Attempt <= 1 {
+			asked++
+		}
+		a := toReportAnswer(answer)
+		testCase := junitCase{Name: a.Question, Classname: "quiz", Time: fmt.Sprintf("%.3f", a.Seconds)}
+		if a.Attempt > 1 {
+			testCase.Name += fmt.Sprintf(" (attempt %d)", a.Attempt)
+		}
+		if a.Category != "" {
+			testCase.Classname += "." + a.Category
+		}
+		if !a.Correct {
+			suite.Failures++
+			got := a.Input
+			if a.Choice != "" {
+				got = a.Choice
+			}
+			message := fmt.Sprintf("expected %q, got %q", a.Answer, got)
+			if a.TimedOut {
+				message = "ran out of time"
+			}
+			testCase.Failure = &junitFailure{Message: message}
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	for _, question := range result.Unanswered {
+		suite.Cases = append(suite.Cases, skippedCase(question.Text, question.Category))
+		suite.Skipped++
+	}
+	// the questions an adaptive game would have picked are unknown
+	for n := asked + suite.Skipped + 1; n <= result.MaxScore; n++ {
+		suite.Cases = append(suite.Cases, skippedCase(fmt.Sprintf("question %d", n), ""))
+		suite.Skipped++
+	}
+	suite.Tests = len(suite.Cases)
+	io.WriteString(w, xml.Header)
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "\t")
+	err := encoder.Encode(suite)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
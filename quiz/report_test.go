@@ -0,0 +1,88 @@
+package quiz
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+	"time"
+)
+
+func TestWriteReport(t *testing.T) {
+	result := Result{
+		Answers: []Answer{
+			{Question: Question{Text: "1+4", Answer: "5"}, Input: "5", Correct: true, Duration: time.Second},
+			{Question: Question{Text: "Capital of France?", Answer: "Paris", Category: "geography"}, Input: "Rome", Duration: 2 * time.Second},
+			{Question: Question{Text: "10/5", Answer: "2"}, TimedOut: true, Duration: 5 * time.Second},
+		},
+		Unanswered: []Question{{Text: "2*3", Answer: "6"}},
+		Score:      1,
+		MaxScore:   4,
+		Ending:     OutOfTime,
+		Seed:       42,
+		Duration:   10 * time.Second,
+	}
+
+	t.Run("JSON report should hold the whole result", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		if err := WriteReport(buf, result, "json"); err != nil {
+			t.Fatal(err)
+		}
+		var report struct {
+			Score   int
+			Ending  string
+			Seed    int64
+			Answers []reportAnswer
+		}
+		if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+			t.Fatal(err)
+		}
+		if report.Score != 1 || report.Ending != "timeout" || report.Seed != 42 || len(report.Answers) != 3 {
+			t.Fatalf("Expected the result to be reported, got %+v", report)
+		}
+		if !report.Answers[2].TimedOut || report.Answers[1].Input != "Rome" {
+			t.Fatalf("Expected every answer to be reported, got %+v", report.Answers)
+		}
+	})
+
+	t.Run("CSV report should have a header and a record per answer", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		if err := WriteReport(buf, result, "csv"); err != nil {
+			t.Fatal(err)
+		}
+		records, err := csv.NewReader(buf).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != 4 || records[2][0] != "Capital of France?" || records[2][6] != "false" {
+			t.Fatalf("Expected a header and 3 answers, got %v", records)
+		}
+	})
+
+	t.Run("JUnit report should fail the incorrect answers", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		if err := WriteReport(buf, result, "junit"); err != nil {
+			t.Fatal(err)
+		}
+		var suite junitSuite
+		if err := xml.Unmarshal(buf.Bytes(), &suite); err != nil {
+			t.Fatal(err)
+		}
+		if suite.Tests != 4 || suite.Failures != 2 || suite.Skipped != 1 {
+			t.Fatalf("Expected 4 tests, 2 failures and 1 skipped, got %+v", suite)
+		}
+		if suite.Cases[3].Name != "2*3" || suite.Cases[3].Skipped == nil {
+			t.Fatalf("Expected the unanswered question to be skipped, got %+v", suite.Cases[3])
+		}
+		if suite.Cases[0].Failure != nil || suite.Cases[1].Classname != "quiz.geography" {
+			t.Fatalf("Expected the correct answer to pass and categories as class names, got %+v", suite.Cases)
+		}
+	})
+
+	t.Run("Unknown formats should be rejected", func(t *testing.T) {
+		if err := WriteReport(&bytes.Buffer{}, result, "pdf"); err != errUnknownFormat {
+			t.Fatalf("Expected error %v, got %v", errUnknownFormat, err)
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package quiz
+
+import (
+	"strings"
+	"time"
+)
+
+// Ending tells how a game came to an end
+type Ending int
+
+const (
+	// Completed means every question was asked
+	Completed Ending = iota
+	// Quit means the player entered the quit keyword
+	Quit
+	// OutOfTime means the time limit of the whole game ran out
+	OutOfTime
+)
+
+func (e Ending) String() string {
+	switch e {
+	case Completed:
+		return "completed"
+	case Quit:
+		return "quit"
+	case OutOfTime:
+		return "timeout"
+	default:
+		return "unknown"
+	}
+}
+
+// Result is the outcome of a game: every question that was asked, in
+// order, with how it was answered, and how the game ended
+type Result struct {
+	Answers []Answer
+	// Unanswered are the questions of the game the player never answered, as
+	// it ended first, in the order they'd have been asked. Adaptive games
+	// pick their questions as they go, so theirs are unknown and left out
+	Unanswered []Question
+	// Score is the number of correct answers, out of MaxScore questions
+	Score    int
+	MaxScore int
+	// FirstTry is the number of questions answered correctly the first
+	// time they were asked. In practice mode the rest of Score was only
+	// got right on a retry
+	FirstTry int
+	// Points is the score weighed by the game's Scorer
+	Points float64
+	// Skill estimates the player's skill on the scale of the questions'
+	// difficulties, in adaptive games
+	Skill  float64
+	Ending Ending
+	// Seed is the seed the game's random choices were drawn from
+	Seed int64
+	// Duration is how long the game lasted, from the first question on
+	Duration time.Duration
+	// Snapshot saves the game when the player quit before the end, so that
+	// it can be resumed with Config.Resume
+	Snapshot *Snapshot
+}
+
+// CategoryScore is how the player did on the questions of a category
+type CategoryScore struct {
+	Category string
+	Score    int
+	// Asked is the number of questions of the category asked
+	Asked int
+}
+
+// Categories breaks the score down by category, in the order categories
+// were first asked. Categories are told apart regardless of case, like
+// Config.Category, and named as first asked. Questions without a category
+// are left out
+func (r Result) Categories() []CategoryScore {
+	var scores []CategoryScore
+	index := make(map[string]int)
+	for _, answer := range r.Answers {
+		category := answer.Question.Category
+		if len(category) == 0 {
+			continue
+		}
+		key := strings.ToLower(category)
+		i, ok := index[key]
+		if !ok {
+			i = len(scores)
+			index[key] = i
+			scores = append(scores, CategoryScore{Category: category})
+		}
+		// in practice mode a question is only asked once, however many
+		// times it's retried
+		if answer.Attempt <= 1 {
+			scores[i].Asked++
+		}
+		if answer.Correct {
+			scores[i].Score++
+		}
+	}
+	return scores
+}
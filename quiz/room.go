@@ -0,0 +1,396 @@
+package quiz
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultRoundTime is how long a round of a multiplayer game lasts when
+// neither the game nor the question has a time limit of its own
+const defaultRoundTime = 20 * time.Second
+
+// Lobby is a line-based TCP server, usable with nc or telnet, where a
+// host creates a room and other players join it to play a deck together.
+// Every question is asked to all the players of a room at once, and the
+// first correct answer wins the point
+type Lobby struct {
+	deck   Deck
+	config Config
+	sleepy sleeper
+	mu     sync.Mutex
+	rooms  map[string]*room
+}
+
+// NewLobby loads the questions of source once, and returns a Lobby whose
+// rooms play them with the options in config. Each round lasts for the
+// time limit of its question, see Config.QuestionTimer, or 20 seconds
+func NewLobby(source QuestionSource, config Config) (*Lobby, error) {
+	deck, err := source.Questions()
+	if err != nil {
+		return nil, err
+	}
+	return &Lobby{deck: deck, config: config, sleepy: &realSleeper{}, rooms: make(map[string]*room)}, nil
+}
+
+// Serve accepts players on listener until it fails
+func (l *Lobby) Serve(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		go l.handle(conn)
+	}
+}
+
+// roomPlayer is a player connected to the lobby. Lines sent to the
+// player are buffered so that a slow connection doesn't hold up the room
+type roomPlayer struct {
+	name string
+	out  chan string
+}
+
+// send queues line for the player, dropping it if they can't keep up
+func (p *roomPlayer) send(line string) {
+	select {
+	case p.out <- line:
+	default:
+	}
+}
+
+// handle talks to a player from the moment they connect: it asks their
+// name, puts them in a room, and then forwards their answers to the room
+func (l *Lobby) handle(conn net.Conn) {
+	defer conn.Close()
+	player := &roomPlayer{out: make(chan string, 64)}
+	gone := make(chan struct{})
+	defer close(gone)
+	go func() {
+		for {
+			select {
+			case line := <-player.out:
+				fmt.Fprintln(conn, line)
+			case <-gone:
+				return
+			}
+		}
+	}()
+
+	messages := l.config.messages()
+	quit := messages.Quit
+	lines := readLines(conn)
+	player.send(messages.fill(messages.LobbyWelcome, MessageData{}))
+	name, ok := <-lines
+	if !ok || strings.TrimSpace(name) == quit {
+		return
+	}
+	player.name = strings.TrimSpace(name)
+
+	var r *room
+	for r == nil {
+		player.send(messages.fill(messages.LobbyMenu, MessageData{}))
+		line, ok := <-lines
+		if !ok || strings.TrimSpace(line) == quit {
+			return
+		}
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 1 && fields[0] == "create":
+			r = l.createRoom(player)
+		case len(fields) == 2 && fields[0] == "join":
+			if r = l.room(fields[1]); r == nil {
+				player.send(messages.fill(messages.NoSuchRoom, MessageData{}))
+			}
+		}
+	}
+
+	r.send(roomEvent{kind: playerJoined, player: player})
+	// telnet ends lines with \r\n, so answers are trimmed
+	for line := range lines {
+		line = strings.TrimSpace(line)
+		if line == quit && r.quits(player, line) {
+			break
+		}
+		r.send(roomEvent{kind: playerInput, player: player, line: line})
+	}
+	r.send(roomEvent{kind: playerLeft, player: player})
+}
+
+// createRoom opens a new room hosted by host, with a fresh code
+func (l *Lobby) createRoom(host *roomPlayer) *room {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	code := roomCode()
+	for l.rooms[code] != nil {
+		code = roomCode()
+	}
+	r := &room{
+		code:     code,
+		host:     host,
+		deck:     l.deck,
+		config:   l.config,
+		messages: l.config.messages(),
+		sleepy:   l.sleepy,
+		events:   make(chan roomEvent),
+		closed:   make(chan struct{}),
+		scores:   make(map[*roomPlayer]int),
+	}
+	l.rooms[code] = r
+	go func() {
+		r.run()
+		l.mu.Lock()
+		delete(l.rooms, code)
+		l.mu.Unlock()
+	}()
+	host.send(r.say(r.messages.RoomCreated, MessageData{Room: code}))
+	return r
+}
+
+// room returns the open room with code, or nil
+func (l *Lobby) room(code string) *room {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rooms[strings.ToUpper(code)]
+}
+
+// roomCode returns a random code of four letters
+func roomCode() string {
+	code := make([]byte, 4)
+	for i := range code {
+		code[i] = byte('A' + rand.Intn(26))
+	}
+	return string(code)
+}
+
+type roomEventKind int
+
+const (
+	playerJoined roomEventKind = iota
+	playerLeft
+	playerInput
+	playerQuitting
+	roundTimedOut
+)
+
+// roomEvent is something that happened in a room: a player joined, left,
+// entered a line or the quit keyword, or a round ran out of time. The room
+// replies to a player quitting with whether they leave
+type roomEvent struct {
+	kind   roomEventKind
+	player *roomPlayer
+	line   string
+	round  int
+	reply  chan bool
+}
+
+// room is a multiplayer game. All of its state is owned by the goroutine
+// running run, and everything else talks to it through events, so the
+// scores need no locking however many players answer at once
+type room struct {
+	code   string
+	host   *roomPlayer
+	deck   Deck
+	config Config
+	// messages are the config's, looked up once
+	messages Messages
+	sleepy   sleeper
+	events   chan roomEvent
+	closed   chan struct{}
+
+	players []*roomPlayer
+	scores  map[*roomPlayer]int
+	playing bool
+	game    Deck
+	// round counts every round ever played in the room, so that the timer
+	// of a round that's already over can be told apart
+	round    int
+	position int
+	answered map[*roomPlayer]bool
+}
+
+// send hands event to the room, unless the room is closed
+func (r *room) send(event roomEvent) {
+	select {
+	case r.events <- event:
+	case <-r.closed:
+	}
+}
+
+// quits reports whether the quit keyword line makes player leave the room,
+// rather than pick the option of the question lettered like it
+func (r *room) quits(player *roomPlayer, line string) bool {
+	reply := make(chan bool, 1)
+	r.send(roomEvent{kind: playerQuitting, player: player, line: line, reply: reply})
+	select {
+	case quits := <-reply:
+		return quits
+	case <-r.closed:
+		return true
+	}
+}
+
+// run handles the events of the room until every player has left
+func (r *room) run() {
+	defer close(r.closed)
+	for event := range r.events {
+		switch event.kind {
+		case playerJoined:
+			r.players = append(r.players, event.player)
+			r.broadcast(r.say(r.messages.RoomJoined, MessageData{Player: event.player.name, Room: r.code}))
+			if !r.playing {
+				event.player.send(r.say(r.messages.Waiting, MessageData{}))
+			}
+		case playerLeft:
+			r.remove(event.player)
+			if len(r.players) == 0 {
+				return
+			}
+			r.broadcast(r.say(r.messages.RoomLeft, MessageData{Player: event.player.name}))
+			if r.playing && r.everyoneAnswered() {
+				r.endRound(nil)
+			}
+		case playerInput:
+			r.handleInput(event.player, event.line)
+		case playerQuitting:
+			picked := false
+			if r.playing {
+				_, picked = r.game[r.position].choose(event.line)
+			}
+			event.reply <- !picked
+		case roundTimedOut:
+			if r.playing && event.round == r.round {
+				r.endRound(nil)
+			}
+		}
+	}
+}
+
+func (r *room) handleInput(player *roomPlayer, line string) {
+	if !r.playing {
+		if player == r.host && line == "start" {
+			r.start()
+		} else {
+			player.send(r.say(r.messages.Waiting, MessageData{}))
+		}
+		return
+	}
+	if r.answered[player] {
+		player.send(r.say(r.messages.AlreadyAnswered, MessageData{}))
+		return
+	}
+	r.answered[player] = true
+	question := r.game[r.position]
+	if check(question, line).Correct {
+		r.scores[player]++
+		r.endRound(player)
+		return
+	}
+	player.send(r.say(r.messages.Wrong, MessageData{}))
+	if r.everyoneAnswered() {
+		r.endRound(nil)
+	}
+}
+
+// start starts a new game, with every score back to zero. A game with no
+// questions, e.g. filtered down to nothing, isn't started
+func (r *room) start() {
+	r.game, _ = r.config.arrange(r.deck, &realClock{})
+	if len(r.game) == 0 {
+		r.host.send(r.say(r.messages.NoQuestions, MessageData{}))
+		return
+	}
+	r.scores = make(map[*roomPlayer]int)
+	r.position = 0
+	r.playing = true
+	r.ask()
+}
+
+// ask asks the current question to every player, and starts its timer
+func (r *room) ask() {
+	r.round++
+	r.answered = make(map[*roomPlayer]bool)
+	question := r.game[r.position]
+	r.broadcast(r.say(r.messages.Progress, MessageData{Number: r.position + 1, Total: len(r.game)}))
+	r.broadcast(question.Text)
+	for i, option := range question.Options {
+		r.broadcast(optionLabel(i) + " " + option)
+	}
+	limit := r.config.questionTimer(question)
+	if limit <= 0 {
+		limit = defaultRoundTime
+	}
+	go func(round int) {
+		r.sleepy.Sleep(limit)
+		r.send(roomEvent{kind: roundTimedOut, round: round})
+	}(r.round)
+}
+
+// endRound reveals the answer, crediting winner if anyone got it, shows
+// the scoreboard and moves on to the next question
+func (r *room) endRound(winner *roomPlayer) {
+	question := r.game[r.position]
+	if winner != nil {
+		r.broadcast(r.say(r.messages.Right, MessageData{Player: winner.name, Answer: question.Answer}))
+	} else {
+		r.broadcast(r.say(r.messages.Nobody, MessageData{Answer: question.Answer}))
+	}
+	r.broadcastScoreboard()
+	r.position++
+	if r.position < len(r.game) {
+		r.ask()
+		return
+	}
+	r.playing = false
+	r.round++
+	r.broadcast(r.say(r.messages.GameOver, MessageData{}))
+}
+
+func (r *room) everyoneAnswered() bool {
+	for _, player := range r.players {
+		if !r.answered[player] {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *room) remove(player *roomPlayer) {
+	for i, p := range r.players {
+		if p == player {
+			r.players = append(r.players[:i], r.players[i+1:]...)
+			break
+		}
+	}
+	if player == r.host && len(r.players) > 0 {
+		r.host = r.players[0]
+	}
+}
+
+func (r *room) broadcast(line string) {
+	for _, player := range r.players {
+		player.send(line)
+	}
+}
+
+// say fills message, one of the room's messages, with data
+func (r *room) say(message string, data MessageData) string {
+	return r.messages.fill(message, data)
+}
+
+// broadcastScoreboard shows every player's score, best first
+func (r *room) broadcastScoreboard() {
+	ranked := make([]*roomPlayer, len(r.players))
+	copy(ranked, r.players)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return r.scores[ranked[i]] > r.scores[ranked[j]]
+	})
+	r.broadcast(r.say(r.messages.Scoreboard, MessageData{}))
+	for i, player := range ranked {
+		r.broadcast(r.say(r.messages.Rank, MessageData{Number: i + 1, Player: player.name, Score: r.scores[player]}))
+	}
+}
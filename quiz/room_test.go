@@ -0,0 +1,227 @@
+package quiz
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLobby(t *testing.T) {
+	deck := Deck{
+		{Text: "1+4", Answer: "5"},
+		{Text: "Capital of France?", Answer: "Paris", Options: []string{"London", "Paris"}},
+	}
+
+	t.Run("The first correct answer should win the point", func(t *testing.T) {
+		address := startLobby(t, deck, &expiringSleeper{})
+		ann := newRoomClient(t, address, "ann")
+		code := ann.create(t)
+		bob := newRoomClient(t, address, "bob")
+		bob.join(t, code)
+		ann.expect(t, "bob joined room "+code)
+
+		ann.say(t, "start")
+		bob.expect(t, "1+4")
+		bob.say(t, "5")
+		ann.expect(t, English.fill(English.Right, MessageData{Player: "bob", Answer: "5"}))
+		ann.expect(t, "1. bob 1")
+
+		ann.expect(t, "b) Paris")
+		ann.say(t, "a")
+		ann.expect(t, English.Wrong)
+		bob.expect(t, "Capital of France?")
+		bob.say(t, "b")
+		bob.expect(t, English.fill(English.Right, MessageData{Player: "bob", Answer: "Paris"}))
+		bob.expect(t, "1. bob 2")
+		bob.expect(t, "2. ann 0")
+		bob.expect(t, English.GameOver)
+	})
+
+	t.Run("Only one player should score when everyone answers at once", func(t *testing.T) {
+		address := startLobby(t, deck[:1], &expiringSleeper{})
+		host := newRoomClient(t, address, "host")
+		code := host.create(t)
+		var players []*roomClient
+		for i := 0; i < 5; i++ {
+			player := newRoomClient(t, address, fmt.Sprintf("player%d", i))
+			player.join(t, code)
+			host.expect(t, fmt.Sprintf("player%d joined", i))
+			players = append(players, player)
+		}
+
+		host.say(t, "start")
+		for _, player := range players {
+			player.expect(t, "1+4")
+		}
+		var wg sync.WaitGroup
+		for _, player := range players {
+			wg.Add(1)
+			go func(player *roomClient) {
+				defer wg.Done()
+				fmt.Fprintln(player.conn, "5")
+			}(player)
+		}
+		wg.Wait()
+
+		host.expect(t, "got it!")
+		host.expect(t, English.Scoreboard)
+		var total int
+		for i := 0; i < len(players)+1; i++ {
+			var rank, score int
+			var name string
+			line := host.line(t)
+			if _, err := fmt.Sscanf(line, "%d. %s %d", &rank, &name, &score); err != nil {
+				t.Fatalf("Expected a scoreboard line, got %q", line)
+			}
+			total += score
+		}
+		if total != 1 {
+			t.Fatalf("Expected a single point to be won, got %d", total)
+		}
+	})
+
+	t.Run("A round should end when its time runs out", func(t *testing.T) {
+		address := startLobby(t, deck[:1], &expiringSleeper{expire: defaultRoundTime})
+		ann := newRoomClient(t, address, "ann")
+		ann.create(t)
+		ann.say(t, "start")
+		ann.expect(t, English.fill(English.Nobody, MessageData{Answer: "5"}))
+		ann.expect(t, English.GameOver)
+	})
+
+	t.Run("The quit keyword should pick the option it letters", func(t *testing.T) {
+		options := make([]string, 17)
+		for i := range options {
+			options[i] = fmt.Sprint(i)
+		}
+		address := startLobby(t, Deck{{Text: "Pick the last", Answer: "16", Options: options}}, &expiringSleeper{})
+		ann := newRoomClient(t, address, "ann")
+		ann.create(t)
+		ann.say(t, "start")
+		ann.expect(t, "q) 16")
+		ann.say(t, "q")
+		ann.expect(t, English.fill(English.Right, MessageData{Player: "ann", Answer: "16"}))
+	})
+
+	t.Run("A room without questions should refuse to start", func(t *testing.T) {
+		address := startLobby(t, Deck{}, &expiringSleeper{})
+		ann := newRoomClient(t, address, "ann")
+		ann.create(t)
+		ann.say(t, "start")
+		ann.expect(t, English.NoQuestions)
+		ann.say(t, "start")
+		ann.expect(t, English.NoQuestions)
+	})
+
+	t.Run("A lobby should talk in the language of its messages", func(t *testing.T) {
+		address := startLobbyWith(t, deck[:1], Config{Messages: french}, &expiringSleeper{expire: defaultRoundTime})
+		conn, err := net.Dial("tcp", address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		ann := &roomClient{conn: conn, reader: bufio.NewReader(conn)}
+		ann.expect(t, french.LobbyWelcome)
+		ann.say(t, "ann")
+		ann.say(t, "create")
+		ann.expect(t, "Salle ")
+		ann.say(t, "start")
+		ann.expect(t, french.fill(french.Progress, MessageData{Number: 1, Total: 1}))
+		ann.expect(t, french.fill(french.Nobody, MessageData{Answer: "5"}))
+		ann.expect(t, french.GameOver)
+	})
+
+	t.Run("Joining a room that doesn't exist should be refused", func(t *testing.T) {
+		address := startLobby(t, deck, &expiringSleeper{})
+		ann := newRoomClient(t, address, "ann")
+		ann.expect(t, English.fill(English.LobbyMenu, MessageData{}))
+		ann.say(t, "join NOPE")
+		ann.expect(t, English.NoSuchRoom)
+	})
+}
+
+func startLobby(t *testing.T, deck Deck, sleepy sleeper) string {
+	t.Helper()
+	return startLobbyWith(t, deck, Config{}, sleepy)
+}
+
+func startLobbyWith(t *testing.T, deck Deck, config Config, sleepy sleeper) string {
+	t.Helper()
+	lobby, err := NewLobby(deck, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lobby.sleepy = sleepy
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go lobby.Serve(listener)
+	return listener.Addr().String()
+}
+
+// roomClient is a player connected to a lobby, as if with nc
+type roomClient struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func newRoomClient(t *testing.T, address, name string) *roomClient {
+	t.Helper()
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	client := &roomClient{conn: conn, reader: bufio.NewReader(conn)}
+	client.expect(t, English.LobbyWelcome)
+	client.say(t, name)
+	return client
+}
+
+func (c *roomClient) say(t *testing.T, line string) {
+	t.Helper()
+	if _, err := fmt.Fprintln(c.conn, line); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (c *roomClient) line(t *testing.T) string {
+	t.Helper()
+	c.conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		t.Fatalf("Expected a line, got %v", err)
+	}
+	return strings.TrimSuffix(line, "\n")
+}
+
+// expect skips lines until one containing want
+func (c *roomClient) expect(t *testing.T, want string) string {
+	t.Helper()
+	for {
+		line := c.line(t)
+		if strings.Contains(line, want) {
+			return line
+		}
+	}
+}
+
+// create creates a room and returns its code
+func (c *roomClient) create(t *testing.T) string {
+	t.Helper()
+	c.say(t, "create")
+	line := c.expect(t, "Created room ")
+	return strings.Fields(line)[2][:4]
+}
+
+func (c *roomClient) join(t *testing.T, code string) {
+	t.Helper()
+	c.say(t, "join "+code)
+	c.expect(t, English.Waiting)
+}
@@ -0,0 +1,71 @@
+package quiz
+
+import (
+	"math"
+	"time"
+)
+
+// Scorer weighs the answers of a game into points, so that games can have
+// their own scoring rules. Answers are given in the order they were
+// answered
+type Scorer interface {
+	Score(answers []Answer) float64
+}
+
+// WeightedScorer gives every correct answer the Points of its question, or
+// 1 if it has none, with optional penalties and bonuses on top. The zero
+// value just adds up the points of the questions answered correctly
+type WeightedScorer struct {
+	// Penalty is taken off for every wrong answer, timeouts included
+	Penalty float64
+	// StreakBonus is the extra fraction of points earned for every correct
+	// answer in a row before this one, e.g. with 0.5 the third correct
+	// answer in a row is worth double. StreakCap, if positive, caps how
+	// long a streak can count for
+	StreakBonus float64
+	StreakCap   int
+	// SpeedBonus is the extra fraction of points earned by an instant
+	// answer. It shrinks linearly with the time taken, down to nothing for
+	// answers that took SpeedLimit or longer
+	SpeedBonus float64
+	SpeedLimit time.Duration
+	// RetryPenalty is the fraction of points lost for every time a question
+	// was asked before, in practice mode, so that questions got right on a
+	// retry only get partial credit
+	RetryPenalty float64
+}
+
+// Score adds up the points of answers
+func (w WeightedScorer) Score(answers []Answer) float64 {
+	var total float64
+	streak := 0
+	for _, answer := range answers {
+		if !answer.Correct {
+			total -= w.Penalty
+			streak = 0
+			continue
+		}
+		points := float64(answer.Question.Points)
+		if points <= 0 {
+			points = 1
+		}
+		multiplier := 1.0
+		if streak > 0 {
+			counted := streak
+			if w.StreakCap > 0 && counted > w.StreakCap {
+				counted = w.StreakCap
+			}
+			multiplier += w.StreakBonus * float64(counted)
+		}
+		if w.SpeedBonus > 0 && w.SpeedLimit > 0 && answer.Duration < w.SpeedLimit {
+			multiplier += w.SpeedBonus * float64(w.SpeedLimit-answer.Duration) / float64(w.SpeedLimit)
+		}
+		if answer.Attempt > 1 {
+			multiplier *= math.Max(0, 1-w.RetryPenalty*float64(answer.Attempt-1))
+		}
+		total += points * multiplier
+		streak++
+	}
+	// round off the noise of float arithmetic, e.g. 3.0000000000000004
+	return math.Round(total*100) / 100
+}
@@ -0,0 +1,55 @@
+package quiz
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWeightedScorer(t *testing.T) {
+	right := func(points int) Answer {
+		return Answer{Question: Question{Points: points}, Correct: true, Attempt: 1}
+	}
+	wrong := Answer{Question: Question{Points: 3}, Attempt: 1}
+
+	cases := []struct {
+		name    string
+		scorer  WeightedScorer
+		answers []Answer
+		want    float64
+	}{
+		{"Correct answers should earn the points of their question", WeightedScorer{}, []Answer{right(3), right(0), wrong}, 4},
+		{"Wrong answers should cost the penalty", WeightedScorer{Penalty: 0.5}, []Answer{right(1), wrong, wrong}, 0},
+		{"Streaks should multiply the points", WeightedScorer{StreakBonus: 0.5}, []Answer{right(2), right(2), right(2), wrong, right(2)}, 2 + 3 + 4 + 2},
+		{"Streaks should stop counting at their cap", WeightedScorer{StreakBonus: 1, StreakCap: 1}, []Answer{right(1), right(1), right(1)}, 1 + 2 + 2},
+		{"Retries should only earn partial credit", WeightedScorer{RetryPenalty: 0.25}, []Answer{wrong, {Question: Question{Points: 4}, Correct: true, Attempt: 3}}, 2},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.scorer.Score(c.answers); got != c.want {
+				t.Fatalf("Expected %v points, got %v", c.want, got)
+			}
+		})
+	}
+
+	t.Run("Quick answers should earn a speed bonus", func(t *testing.T) {
+		scorer := WeightedScorer{SpeedBonus: 1, SpeedLimit: 10 * time.Second}
+		quick, slow := right(2), right(2)
+		quick.Duration = 2500 * time.Millisecond
+		slow.Duration = 15 * time.Second
+		if got := scorer.Score([]Answer{quick, slow}); got != 3.5+2 {
+			t.Fatalf("Expected 5.5 points, got %v", got)
+		}
+	})
+
+	t.Run("The final message should show the raw and weighted scores", func(t *testing.T) {
+		printingSpy := &recordingPrinter{}
+		deck := Deck{{Text: "1+4", Answer: "5", Points: 3}, {Text: "2+2", Answer: "4"}}
+		config := Config{Timer: 30, Scorer: WeightedScorer{Penalty: 1}}
+		playGame(deck, config, strings.NewReader("\n5\n3\n"), &expiringSleeper{}, printingSpy)
+		expected := "Thank you for playing. your final score is 1 out of 2 with a weighted score of 2"
+		if last := printingSpy.lines[len(printingSpy.lines)-1]; last != expected {
+			t.Fatalf("Expected %q, got %q", expected, last)
+		}
+	})
+}
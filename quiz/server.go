@@ -0,0 +1,285 @@
+package quiz
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"html/template"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// sessionCookie is the cookie that tells which session a player is in
+const sessionCookie = "quiz_session"
+
+// sessionGrace is how long a game that's over is kept, for its player to
+// see the result, and sessionMaxAge how long any game is kept
+const sessionGrace = 10 * time.Minute
+const sessionMaxAge = 24 * time.Hour
+
+// maxSessions caps the games kept at once, the oldest making way for new
+// ones
+const maxSessions = 10000
+
+var homePage = template.Must(template.New("home").Parse(homeTempl))
+var questionPage = template.Must(template.New("question").Parse(questionTempl))
+var resultPage = template.Must(template.New("result").Parse(resultTempl))
+
+// Server plays the quiz over HTTP, with the same engine as the terminal
+// game. Every player gets their own session, tracked with a cookie, and
+// the time limits are enforced on the server
+type Server struct {
+	deck     Deck
+	config   Config
+	mu       sync.Mutex
+	sessions map[string]*session
+	mux      *http.ServeMux
+	clock    clock
+}
+
+// NewServer loads the questions of source once, and returns a Server
+// playing them with the options in config. When config.Seed is zero
+// every player gets their own shuffle
+func NewServer(source QuestionSource, config Config) (*Server, error) {
+	deck, err := source.Questions()
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{deck: deck, config: config, sessions: make(map[string]*session), clock: &realClock{}}
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("/", s.home)
+	s.mux.HandleFunc("/start", s.start)
+	s.mux.HandleFunc("/question", s.question)
+	s.mux.HandleFunc("/answer", s.answer)
+	s.mux.HandleFunc("/result", s.result)
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// session returns the session of the player making r, or nil if they
+// haven't started a game. The time limits of the session are applied
+// before it's returned, and timedOut tells whether the question being
+// asked ran out of time just now
+func (s *Server) session(r *http.Request) (game *session, timedOut bool) {
+	cookie, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return nil, false
+	}
+	s.mu.Lock()
+	game = s.sessions[cookie.Value]
+	s.mu.Unlock()
+	if game != nil {
+		timedOut = game.expire()
+	}
+	return game, timedOut
+}
+
+func newSessionID() (string, error) {
+	id := make([]byte, 16)
+	_, err := rand.Read(id)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+func (s *Server) home(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	messages := s.config.messages()
+	data := MessageData{Time: time.Duration(s.config.Timer) * time.Second, Total: len(s.deck)}
+	render(w, homePage, struct {
+		Greeting  string
+		Questions string
+		Messages  Messages
+	}{messages.fill(messages.Greeting, data), messages.fill(messages.Questions, data), messages})
+}
+
+// start starts a new game for the player, replacing any game they had
+func (s *Server) start(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, err := newSessionID()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	game := newSession(s.deck, s.config, s.clock)
+	game.start()
+	s.mu.Lock()
+	if cookie, err := r.Cookie(sessionCookie); err == nil {
+		delete(s.sessions, cookie.Value)
+	}
+	s.prune()
+	s.sessions[id] = game
+	s.mu.Unlock()
+	http.SetCookie(w, &http.Cookie{Name: sessionCookie, Value: id, Path: "/", HttpOnly: true})
+	http.Redirect(w, r, "/question", http.StatusSeeOther)
+}
+
+// prune forgets the games that are stale, and the oldest ones if there
+// are still too many. s.mu must be held
+func (s *Server) prune() {
+	for id, game := range s.sessions {
+		if game.stale(sessionGrace, sessionMaxAge) {
+			delete(s.sessions, id)
+		}
+	}
+	for len(s.sessions) >= maxSessions {
+		var oldest string
+		var started time.Time
+		for id, game := range s.sessions {
+			game.mu.Lock()
+			if oldest == "" || game.started.Before(started) {
+				oldest, started = id, game.started
+			}
+			game.mu.Unlock()
+		}
+		delete(s.sessions, oldest)
+	}
+}
+
+// question shows the question being asked, or the result once the game
+// is over
+func (s *Server) question(w http.ResponseWriter, r *http.Request) {
+	game, _ := s.session(r)
+	if game == nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+	question, ok := game.question()
+	if !ok {
+		game.end(Completed)
+		http.Redirect(w, r, "/result", http.StatusSeeOther)
+		return
+	}
+	number, total := game.progress()
+	type option struct {
+		Letter string
+		Label  string
+		Text   string
+	}
+	options := make([]option, 0, len(question.Options))
+	for i, text := range question.Options {
+		options = append(options, option{string(rune('a' + i)), optionLabel(i), text})
+	}
+	messages := game.config.messages()
+	// the time left and the question's limit are only shown if there's one
+	var remaining, limit string
+	if left := game.remaining().Round(time.Second); left > 0 {
+		remaining = messages.fill(messages.Countdown, MessageData{Time: left})
+	}
+	if questionLimit := game.config.questionTimer(question); questionLimit > 0 {
+		limit = messages.fill(messages.QuestionTime, MessageData{Time: questionLimit})
+	}
+	render(w, questionPage, struct {
+		Number    int
+		Progress  string
+		Missed    []string
+		Question  Question
+		Options   []option
+		Remaining string
+		Limit     string
+		Messages  Messages
+	}{
+		Number:    number,
+		Progress:  messages.fill(messages.Progress, MessageData{Number: number, Total: total}),
+		Missed:    missed(game),
+		Question:  question,
+		Options:   options,
+		Remaining: remaining,
+		Limit:     limit,
+		Messages:  messages,
+	})
+}
+
+// answer grades the player's answer to the question the form was shown
+// for, or ends the game if they quit
+func (s *Server) answer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	game, timedOut := s.session(r)
+	if game == nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+	if timedOut {
+		// the answer is to a question that's gone, not to the next one
+		http.Redirect(w, r, "/question", http.StatusSeeOther)
+		return
+	}
+	if r.FormValue("quit") != "" {
+		game.end(Quit)
+		http.Redirect(w, r, "/result", http.StatusSeeOther)
+		return
+	}
+	// the form says which question it answers, so that posting it twice
+	// doesn't answer the next question too
+	number, _ := strconv.Atoi(r.FormValue("number"))
+	if current, _ := game.progress(); number != current {
+		http.Redirect(w, r, "/question", http.StatusSeeOther)
+		return
+	}
+	userInput := r.FormValue("answer")
+	question, _ := game.question()
+	if question.quits(userInput, game.config.messages().Quit) {
+		game.end(Quit)
+		http.Redirect(w, r, "/result", http.StatusSeeOther)
+		return
+	}
+	game.answerAt(number, userInput)
+	http.Redirect(w, r, "/question", http.StatusSeeOther)
+}
+
+// result shows how the player did
+func (s *Server) result(w http.ResponseWriter, r *http.Request) {
+	game, _ := s.session(r)
+	if game == nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+	if !game.isOver() {
+		http.Redirect(w, r, "/question", http.StatusSeeOther)
+		return
+	}
+	result := game.result()
+	messages := game.config.messages()
+	message := messages.Bye
+	if result.Ending == OutOfTime {
+		message = messages.TimeOut
+	}
+	render(w, resultPage, struct {
+		Message  string
+		Missed   []string
+		Result   Result
+		Messages Messages
+	}{messages.fill(message, resultData(result)), missed(game), result, messages})
+}
+
+// missed tells the player about the question they were last asked if they
+// missed it, the way the terminal game does right after their answer
+func missed(game *session) []string {
+	answer, ok := game.last()
+	if !ok || answer.Correct {
+		return nil
+	}
+	return missNotes(answer.Question, game.config, game.config.messages())
+}
+
+func render(w http.ResponseWriter, page *template.Template, data interface{}) {
+	w.Header().Set("Content-Type", "text/html")
+	err := page.Execute(w, data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
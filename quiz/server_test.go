@@ -0,0 +1,262 @@
+package quiz
+
+import (
+	"html"
+	"io/ioutil"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServer(t *testing.T) {
+	deck := Deck{
+		{Text: "1+4", Answer: "5"},
+		{Text: "Capital of France?", Answer: "Paris", Options: []string{"London", "Paris"}},
+	}
+	quizServer, err := NewServer(deck, Config{Timer: 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(quizServer)
+	defer server.Close()
+
+	t.Run("A player should be asked every question and shown their score", func(t *testing.T) {
+		player := newPlayer(t)
+		body := player.post(t, server.URL+"/start", nil)
+		if !strings.Contains(body, "1+4") {
+			t.Fatalf("Expected the first question, got %s", body)
+		}
+		body = player.post(t, server.URL+"/answer", url.Values{"number": {"1"}, "answer": {"5"}})
+		if !strings.Contains(body, "Capital of France?") || !strings.Contains(body, "b) Paris") {
+			t.Fatalf("Expected the second question with its options, got %s", body)
+		}
+		body = player.post(t, server.URL+"/answer", url.Values{"number": {"2"}, "answer": {"a"}})
+		if !strings.Contains(body, "your final score is 1 out of 2") {
+			t.Fatalf("Expected a score of 1 out of 2, got %s", body)
+		}
+	})
+
+	t.Run("Players should have sessions of their own", func(t *testing.T) {
+		ann, bob := newPlayer(t), newPlayer(t)
+		ann.post(t, server.URL+"/start", nil)
+		bob.post(t, server.URL+"/start", nil)
+		ann.post(t, server.URL+"/answer", url.Values{"number": {"1"}, "answer": {"5"}})
+		body := bob.get(t, server.URL+"/question")
+		if !strings.Contains(body, "1+4") {
+			t.Fatalf("Expected bob to still be on the first question, got %s", body)
+		}
+	})
+
+	t.Run("Quitting should end the game", func(t *testing.T) {
+		player := newPlayer(t)
+		player.post(t, server.URL+"/start", nil)
+		body := player.post(t, server.URL+"/answer", url.Values{"quit": {English.Quit}})
+		if !strings.Contains(body, "your final score is 0 out of 2") {
+			t.Fatalf("Expected the game to end, got %s", body)
+		}
+	})
+
+	t.Run("The timer should be enforced by the server", func(t *testing.T) {
+		player := newPlayer(t)
+		player.post(t, server.URL+"/start", nil)
+		// pretend the game started longer ago than the timer allows
+		quizServer.mu.Lock()
+		for _, game := range quizServer.sessions {
+			game.mu.Lock()
+			game.started = game.started.Add(-time.Minute)
+			game.mu.Unlock()
+		}
+		quizServer.mu.Unlock()
+		body := player.post(t, server.URL+"/answer", url.Values{"number": {"1"}, "answer": {"5"}})
+		if !strings.Contains(body, "You ran out of time. Thank you for playing. Your final score is 0 out of 2") {
+			t.Fatalf("Expected the late answer not to count, got %s", body)
+		}
+	})
+
+	t.Run("A late answer should not count for the next question", func(t *testing.T) {
+		timedServer, err := NewServer(deck, Config{Timer: 30, QuestionTimer: 5})
+		if err != nil {
+			t.Fatal(err)
+		}
+		server := httptest.NewServer(timedServer)
+		defer server.Close()
+		player := newPlayer(t)
+		player.post(t, server.URL+"/start", nil)
+		var game *session
+		timedServer.mu.Lock()
+		for _, game = range timedServer.sessions {
+			game.mu.Lock()
+			game.asked = game.asked.Add(-time.Minute)
+			game.mu.Unlock()
+		}
+		timedServer.mu.Unlock()
+		body := player.post(t, server.URL+"/answer", url.Values{"number": {"1"}, "answer": {"b"}})
+		if !strings.Contains(body, "Capital of France?") {
+			t.Fatalf("Expected the second question, got %s", body)
+		}
+		answers := game.answered()
+		if len(answers) != 1 || !answers[0].TimedOut {
+			t.Fatalf("Expected only the first question, timed out, got %+v", answers)
+		}
+	})
+
+	t.Run("An answer posted twice should only count once", func(t *testing.T) {
+		fives := Deck{{Text: "1+4", Answer: "5"}, {Text: "2+3", Answer: "5"}, {Text: "9+9", Answer: "18"}}
+		fivesServer, err := NewServer(fives, Config{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		server := httptest.NewServer(fivesServer)
+		defer server.Close()
+		player := newPlayer(t)
+		player.post(t, server.URL+"/start", nil)
+		form := url.Values{"number": {"1"}, "answer": {"5"}}
+		player.post(t, server.URL+"/answer", form)
+		body := player.post(t, server.URL+"/answer", form)
+		if !strings.Contains(body, "2+3") {
+			t.Fatalf("Expected to still be on the second question, got %s", body)
+		}
+		for _, game := range fivesServer.sessions {
+			if answers := game.answered(); len(answers) != 1 {
+				t.Fatalf("Expected a single answer, got %+v", answers)
+			}
+		}
+	})
+
+	t.Run("A missed question should have its hint and, in practice, its answer shown next", func(t *testing.T) {
+		hinted := Deck{{Text: "1+4", Answer: "5", Hint: "count on your fingers"}, {Text: "2+2", Answer: "4"}}
+		practiceServer, err := NewServer(hinted, Config{Practice: true, Retries: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		server := httptest.NewServer(practiceServer)
+		defer server.Close()
+		player := newPlayer(t)
+		player.post(t, server.URL+"/start", nil)
+		body := player.post(t, server.URL+"/answer", url.Values{"number": {"1"}, "answer": {"6"}})
+		if !strings.Contains(body, "Hint: count on your fingers") || !strings.Contains(body, "the answer is 5") {
+			t.Fatalf("Expected the hint and the answer of the first question, got %s", body)
+		}
+		body = player.post(t, server.URL+"/answer", url.Values{"number": {"2"}, "answer": {"4"}})
+		if strings.Contains(body, "Hint:") || strings.Contains(body, "the answer is") {
+			t.Fatalf("Expected nothing about a question answered right, got %s", body)
+		}
+		body = player.post(t, server.URL+"/answer", url.Values{"number": {"3"}, "answer": {"3"}})
+		if !strings.Contains(body, "the answer is 5") || !strings.Contains(body, "your final score") {
+			t.Fatalf("Expected the answer of the last question missed with the result, got %s", body)
+		}
+	})
+
+	t.Run("A game without a timer should not run out of time", func(t *testing.T) {
+		untimedServer, err := NewServer(deck, Config{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		watch := &fakeClock{now: time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)}
+		untimedServer.clock = watch
+		server := httptest.NewServer(untimedServer)
+		defer server.Close()
+		player := newPlayer(t)
+		player.post(t, server.URL+"/start", nil)
+		body := player.post(t, server.URL+"/answer", url.Values{"number": {"1"}, "answer": {"5"}})
+		if !strings.Contains(body, "Capital of France?") || strings.Contains(body, "left") {
+			t.Fatalf("Expected the second question with no time limit, got %s", body)
+		}
+	})
+
+	t.Run("Games should be forgotten once they're stale", func(t *testing.T) {
+		prunedServer, err := NewServer(deck, Config{Timer: 30})
+		if err != nil {
+			t.Fatal(err)
+		}
+		watch := &fakeClock{now: time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)}
+		prunedServer.clock = watch
+		server := httptest.NewServer(prunedServer)
+		defer server.Close()
+		ann, bob := newPlayer(t), newPlayer(t)
+		ann.post(t, server.URL+"/start", nil)
+		ann.post(t, server.URL+"/answer", url.Values{"quit": {English.Quit}})
+		bob.post(t, server.URL+"/start", nil)
+		if len(prunedServer.sessions) != 2 {
+			t.Fatalf("Expected ann's result to be kept for a while, got %d sessions", len(prunedServer.sessions))
+		}
+		watch.now = watch.now.Add(sessionGrace + time.Minute)
+		newPlayer(t).post(t, server.URL+"/start", nil)
+		if len(prunedServer.sessions) != 1 {
+			t.Fatalf("Expected only the new game to be kept, got %d sessions", len(prunedServer.sessions))
+		}
+	})
+
+	t.Run("Pages should be in the language of the messages", func(t *testing.T) {
+		frenchServer, err := NewServer(deck, Config{Messages: french})
+		if err != nil {
+			t.Fatal(err)
+		}
+		server := httptest.NewServer(frenchServer)
+		defer server.Close()
+		player := newPlayer(t)
+		if body := player.get(t, server.URL+"/"); !strings.Contains(body, "Commencer") {
+			t.Fatalf("Expected a French home page, got %s", body)
+		}
+		body := player.post(t, server.URL+"/start", nil)
+		if !strings.Contains(body, "Question 1 sur 2") || !strings.Contains(body, "Répondre") {
+			t.Fatalf("Expected a French question page, got %s", body)
+		}
+	})
+
+	t.Run("Players without a game should be sent to the home page", func(t *testing.T) {
+		body := newPlayer(t).get(t, server.URL+"/question")
+		if !strings.Contains(body, "Welcome to the maths quiz!") {
+			t.Fatalf("Expected the home page, got %s", body)
+		}
+	})
+}
+
+// player is an HTTP client that keeps its session cookie, like a browser
+type player struct {
+	client *http.Client
+}
+
+func newPlayer(t *testing.T) *player {
+	t.Helper()
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &player{client: &http.Client{Jar: jar}}
+}
+
+func (p *player) get(t *testing.T, target string) string {
+	t.Helper()
+	resp, err := p.client.Get(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return readBody(t, resp)
+}
+
+func (p *player) post(t *testing.T, target string, form url.Values) string {
+	t.Helper()
+	resp, err := p.client.PostForm(target, form)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return readBody(t, resp)
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return html.UnescapeString(string(body))
+}
@@ -0,0 +1,244 @@
+package quiz
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+)
+
+var errSnapshotDeck = errors.New("the saved game was played with another question bank")
+
+// Snapshot is a game saved half-way, to be resumed later with
+// Config.Resume. Questions are saved by their index in the deck the game
+// was made from, which Deck identifies, so a snapshot can only resume a
+// game with the same deck
+type Snapshot struct {
+	// Deck is a checksum of the questions and answers of the deck
+	Deck string `json:"deck"`
+	// Order is every question of the game in the order asked, the ones
+	// answered first
+	Order   []snapshotQuestion `json:"order"`
+	Answers []snapshotAnswer   `json:"answers"`
+	// Attempts maps the index in Order of every question queued again in
+	// practice mode to its attempt number
+	Attempts  map[int]int       `json:"attempts,omitempty"`
+	Adaptive  *snapshotAdaptive `json:"adaptive,omitempty"`
+	Questions int               `json:"questions"`
+	Score     int               `json:"score"`
+	Seed      int64             `json:"seed"`
+	// Practice, Retries and QuestionTimer are the options the game was
+	// played with, which a resumed game keeps
+	Practice      bool `json:"practice"`
+	Retries       int  `json:"retries"`
+	QuestionTimer int  `json:"question_timer"`
+	// Elapsed is how long the game was played, and Remaining how much time
+	// it has left, zero if it has no time limit
+	Elapsed   time.Duration `json:"elapsed"`
+	Remaining time.Duration `json:"remaining"`
+}
+
+type snapshotQuestion struct {
+	Index int `json:"index"`
+	// Options are saved in the order shown, in case they were shuffled
+	Options []string `json:"options,omitempty"`
+}
+
+type snapshotAnswer struct {
+	Input    string        `json:"input"`
+	Choice   string        `json:"choice,omitempty"`
+	Correct  bool          `json:"correct"`
+	TimedOut bool          `json:"timed_out,omitempty"`
+	Duration time.Duration `json:"duration"`
+	Attempt  int           `json:"attempt"`
+}
+
+// snapshotAdaptive is the state of an adaptive game
+type snapshotAdaptive struct {
+	Pool     []snapshotQuestion `json:"pool"`
+	Picked   int                `json:"picked"`
+	Level    int                `json:"level"`
+	MinLevel int                `json:"min_level"`
+	MaxLevel int                `json:"max_level"`
+	Skill    float64            `json:"skill"`
+}
+
+// LoadSnapshot reads the snapshot saved at path
+func LoadSnapshot(path string) (*Snapshot, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var snapshot Snapshot
+	err = json.NewDecoder(file).Decode(&snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// Save writes the snapshot to path, replacing any file there
+func (s *Snapshot) Save(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = json.NewEncoder(file).Encode(s)
+	if errClose := file.Close(); err == nil {
+		err = errClose
+	}
+	return err
+}
+
+// checksum identifies deck by its questions and answers, in order
+func checksum(deck Deck) string {
+	hash := sha256.New()
+	for _, question := range deck {
+		hash.Write([]byte(question.Text))
+		hash.Write([]byte{0})
+		hash.Write([]byte(question.Answer))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// snapshot saves the state of the game. The question being asked, if any,
+// is saved unanswered, to be asked again
+func (s *session) snapshot() *Snapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snapshot := &Snapshot{
+		Deck:          checksum(s.source),
+		Order:         s.saveQuestions(s.deck),
+		Answers:       make([]snapshotAnswer, 0, len(s.answers)),
+		Attempts:      s.attempts,
+		Questions:     s.questions,
+		Seed:          s.seed,
+		Practice:      s.config.Practice,
+		Retries:       s.config.Retries,
+		QuestionTimer: s.config.QuestionTimer,
+		Elapsed:       s.duration,
+	}
+	// a game without a time limit resumes without one
+	if s.limit > 0 {
+		snapshot.Remaining = s.limit - (s.duration - s.elapsed)
+	}
+	for _, answer := range s.answers {
+		if answer.Correct {
+			snapshot.Score++
+		}
+		snapshot.Answers = append(snapshot.Answers, snapshotAnswer{
+			Input:    answer.Input,
+			Choice:   answer.Choice,
+			Correct:  answer.Correct,
+			TimedOut: answer.TimedOut,
+			Duration: answer.Duration,
+			Attempt:  answer.Attempt,
+		})
+	}
+	if s.config.Adaptive {
+		snapshot.Adaptive = &snapshotAdaptive{
+			Pool:     s.saveQuestions(s.pool),
+			Picked:   s.picked,
+			Level:    s.level,
+			MinLevel: s.minLevel,
+			MaxLevel: s.maxLevel,
+			Skill:    s.skill,
+		}
+	}
+	return snapshot
+}
+
+// saveQuestions finds every question of deck in the source deck. Copies
+// of a question, e.g. with shuffled options, are found by their text and
+// answer
+func (s *session) saveQuestions(deck Deck) []snapshotQuestion {
+	saved := make([]snapshotQuestion, 0, len(deck))
+	for _, question := range deck {
+		for i, original := range s.source {
+			if original.Text == question.Text && original.Answer == question.Answer {
+				saved = append(saved, snapshotQuestion{Index: i, Options: question.Options})
+				break
+			}
+		}
+	}
+	return saved
+}
+
+// resumeSession restores the game saved in config.Resume, made from
+// source. The options saved with the game take precedence over config's
+func resumeSession(source Deck, config Config) (*session, error) {
+	snapshot := config.Resume
+	if snapshot.Deck != checksum(source) {
+		return nil, errSnapshotDeck
+	}
+	config.Practice = snapshot.Practice
+	config.Retries = snapshot.Retries
+	config.QuestionTimer = snapshot.QuestionTimer
+	config.Adaptive = snapshot.Adaptive != nil
+	config.Seed = snapshot.Seed
+	deck, err := loadQuestions(source, snapshot.Order)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshot.Answers) > len(deck) {
+		return nil, errSnapshotDeck
+	}
+	s := &session{
+		config:    config,
+		source:    source,
+		deck:      deck,
+		seed:      snapshot.Seed,
+		questions: snapshot.Questions,
+		attempts:  make(map[int]int),
+		limit:     snapshot.Remaining,
+		elapsed:   snapshot.Elapsed,
+		clock:     &realClock{},
+	}
+	for i, saved := range snapshot.Answers {
+		s.answers = append(s.answers, Answer{
+			Question: deck[i],
+			Input:    saved.Input,
+			Choice:   saved.Choice,
+			Correct:  saved.Correct,
+			TimedOut: saved.TimedOut,
+			Duration: saved.Duration,
+			Attempt:  saved.Attempt,
+		})
+	}
+	s.position = len(s.answers)
+	for i, attempt := range snapshot.Attempts {
+		s.attempts[i] = attempt
+	}
+	if adaptive := snapshot.Adaptive; adaptive != nil {
+		s.pool, err = loadQuestions(source, adaptive.Pool)
+		if err != nil {
+			return nil, err
+		}
+		s.picked = adaptive.Picked
+		s.level = adaptive.Level
+		s.minLevel = adaptive.MinLevel
+		s.maxLevel = adaptive.MaxLevel
+		s.skill = adaptive.Skill
+	}
+	return s, nil
+}
+
+// loadQuestions looks the saved questions up in source
+func loadQuestions(source Deck, saved []snapshotQuestion) (Deck, error) {
+	deck := make(Deck, 0, len(saved))
+	for _, question := range saved {
+		if question.Index < 0 || question.Index >= len(source) {
+			return nil, errSnapshotDeck
+		}
+		loaded := source[question.Index]
+		if len(question.Options) > 0 {
+			loaded.Options = question.Options
+		}
+		deck = append(deck, loaded)
+	}
+	return deck, nil
+}
@@ -0,0 +1,106 @@
+package quiz
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSnapshot(t *testing.T) {
+	deck := Deck{
+		{Text: "1+4", Answer: "5"},
+		{Text: "Capital of France?", Answer: "Paris"},
+		{Text: "Capital of Italy?", Answer: "Rome", Options: []string{"Milan", "Rome", "Naples"}},
+		{Text: "2*3", Answer: "6"},
+	}
+	noTimeout := &expiringSleeper{}
+	asked := func(result Result) []string {
+		texts := make([]string, 0, len(result.Answers))
+		for _, answer := range result.Answers {
+			texts = append(texts, answer.Question.Text)
+		}
+		return texts
+	}
+
+	t.Run("A game quit half-way should resume where it was left", func(t *testing.T) {
+		config := Config{Timer: 30, Shuffle: true, ShuffleOptions: true, Seed: 7}
+		whole, _ := playGame(deck, config, strings.NewReader("\nq\n"), noTimeout, &spyPrinter{})
+		order := whole.Snapshot.Order
+
+		first, _ := playGame(deck, config, strings.NewReader("\n0\n0\nq\n"), noTimeout, &spyPrinter{})
+		if first.Ending != Quit || first.Snapshot == nil {
+			t.Fatalf("Expected a quit game with a snapshot, got %+v", first)
+		}
+		if first.Snapshot.Score != 0 || len(first.Snapshot.Answers) != 2 {
+			t.Fatalf("Expected 2 wrong answers saved, got %+v", first.Snapshot)
+		}
+		if first.Snapshot.Remaining <= 0 || first.Snapshot.Remaining > 30*time.Second {
+			t.Fatalf("Expected some of the 30s left, got %v", first.Snapshot.Remaining)
+		}
+
+		path := filepath.Join(t.TempDir(), "game.json")
+		if err := first.Snapshot.Save(path); err != nil {
+			t.Fatalf("Expected no error saving, got %v", err)
+		}
+		snapshot, err := LoadSnapshot(path)
+		if err != nil {
+			t.Fatalf("Expected no error loading, got %v", err)
+		}
+		if !reflect.DeepEqual(snapshot.Order, order) {
+			t.Fatalf("Expected the order %v, got %v", order, snapshot.Order)
+		}
+
+		// the resumed game keeps the saved order, whatever the config says
+		resumed := Config{Timer: 30, Resume: snapshot}
+		var answers []string
+		for _, saved := range order[2:] {
+			question := deck[saved.Index]
+			if len(saved.Options) == 0 {
+				answers = append(answers, question.Answer)
+				continue
+			}
+			for i, option := range saved.Options {
+				if option == question.Answer {
+					answers = append(answers, string(rune('a'+i)))
+				}
+			}
+		}
+		input := "\n" + strings.Join(answers, "\n") + "\n"
+		result, err := playGame(deck, resumed, strings.NewReader(input), noTimeout, &spyPrinter{})
+		if err != nil {
+			t.Fatalf("Expected no error resuming, got %v", err)
+		}
+		if result.Ending != Completed || result.Score != 2 || result.MaxScore != 4 || len(result.Answers) != 4 {
+			t.Fatalf("Expected a completed game with 2 out of 4, got %+v", result)
+		}
+		if result.Snapshot != nil {
+			t.Fatalf("Expected no snapshot of a completed game, got %+v", result.Snapshot)
+		}
+		if !reflect.DeepEqual(asked(result)[:2], asked(first)) {
+			t.Fatalf("Expected the answers saved first, got %v", asked(result))
+		}
+	})
+
+	t.Run("An interrupted game should be saved", func(t *testing.T) {
+		input, _ := io.Pipe()
+		interrupt := make(chan os.Signal, 1)
+		interrupt <- os.Interrupt
+		result, _ := playInterruptible(deck, Config{Timer: 30}, input, noTimeout, &spyPrinter{}, interrupt, &realClock{})
+		if result.Ending != Quit || result.Snapshot == nil || len(result.Snapshot.Order) != len(deck) {
+			t.Fatalf("Expected a quit game with a snapshot of the whole deck, got %+v", result)
+		}
+	})
+
+	t.Run("A snapshot should not resume a game of another deck", func(t *testing.T) {
+		first, _ := playGame(deck, Config{Timer: 30}, strings.NewReader("\nq\n"), noTimeout, &spyPrinter{})
+		_, err := playGame(deck[1:], Config{Timer: 30, Resume: first.Snapshot}, strings.NewReader("\n"), noTimeout, &spyPrinter{})
+		if !errors.Is(err, errSnapshotDeck) {
+			t.Fatalf("Expected errSnapshotDeck, got %v", err)
+		}
+	})
+}
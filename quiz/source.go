@@ -0,0 +1,75 @@
+package quiz
+
+import (
+	"encoding/csv"
+	"io"
+	"io/fs"
+	"os"
+)
+
+// QuestionSource is anything the game can get its questions from, e.g.
+// a CSV file, an in-memory Deck or a generator
+type QuestionSource interface {
+	Questions() (Deck, error)
+}
+
+// Questions returns the deck itself, so that an in-memory Deck can be
+// used directly as a QuestionSource
+func (d Deck) Questions() (Deck, error) {
+	return d, nil
+}
+
+// SourceFunc adapts an ordinary function to a QuestionSource, which is
+// handy for custom generators
+type SourceFunc func() (Deck, error)
+
+// Questions calls f()
+func (f SourceFunc) Questions() (Deck, error) {
+	return f()
+}
+
+// CSVSource reads question/answer pairs in CSV format from Reader,
+// skipping the first record if Header is set
+type CSVSource struct {
+	Reader io.Reader
+	Header bool
+}
+
+// Questions parses the whole CSV into a Deck
+func (c CSVSource) Questions() (Deck, error) {
+	return parseCSV(csv.NewReader(c.Reader), c.Header)
+}
+
+// FSSource reads the question bank Name from the file system FS, in the
+// format given by its extension like File. Header is only used for CSV
+// files, to skip their first record
+type FSSource struct {
+	FS     fs.FS
+	Name   string
+	Header bool
+}
+
+// Questions opens Name in FS and parses it into a Deck
+func (f FSSource) Questions() (Deck, error) {
+	file, err := f.FS.Open(f.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	deck, err := sourceFor(f.Name, file, f.Header).Questions()
+	return deck, inFile(err, f.Name)
+}
+
+// CSVFile returns a QuestionSource reading the CSV at csvPath, skipping
+// the header if there is one
+func CSVFile(csvPath string, header bool) QuestionSource {
+	return SourceFunc(func() (Deck, error) {
+		file, err := os.Open(csvPath)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		deck, err := CSVSource{Reader: file, Header: header}.Questions()
+		return deck, inFile(err, csvPath)
+	})
+}
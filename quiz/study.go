@@ -0,0 +1,201 @@
+package quiz
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+// studyBucket is the top-level bucket holding a nested bucket of cards for
+// every deck
+var studyBucket = []byte("study")
+
+// leitnerIntervals is how long a question waits before it's due again, for
+// every Leitner box. Correct answers move a question up a box, and wrong
+// ones send it back to the first box, to be reviewed straight away
+var leitnerIntervals = []time.Duration{
+	0,
+	24 * time.Hour,
+	3 * 24 * time.Hour,
+	7 * 24 * time.Hour,
+	14 * 24 * time.Hour,
+	30 * 24 * time.Hour,
+}
+
+// Card is the study history of a question
+type Card struct {
+	Box     int       `json:"box"`
+	Due     time.Time `json:"due"`
+	Reviews int       `json:"reviews"`
+	Lapses  int       `json:"lapses"`
+}
+
+// Review returns the card after answering its question at now: up a box if
+// the answer was correct, back to the first box if not
+func (c Card) Review(correct bool, now time.Time) Card {
+	if correct {
+		if c.Box < len(leitnerIntervals)-1 {
+			c.Box++
+		}
+	} else {
+		c.Box = 0
+		c.Lapses++
+	}
+	c.Reviews++
+	c.Due = now.Add(leitnerIntervals[c.Box])
+	return c
+}
+
+// Cards maps the text of every question studied to its card
+type Cards map[string]Card
+
+// Due returns the questions of deck that are due for review at now: the
+// ones studied before whose time has come, most overdue first, then the
+// ones never studied, in deck order
+func (c Cards) Due(deck Deck, now time.Time) Deck {
+	due := make(Deck, 0, len(deck))
+	for _, question := range deck {
+		if card, ok := c[question.Text]; !ok || !card.Due.After(now) {
+			due = append(due, question)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		first, studied := c[due[i].Text]
+		second, alsoStudied := c[due[j].Text]
+		if studied != alsoStudied {
+			return studied
+		}
+		return first.Due.Before(second.Due)
+	})
+	return due
+}
+
+// Forecast counts the questions of deck due for review on each of the
+// next days, starting with the day of now. Questions that are overdue or
+// never studied count as due today
+func (c Cards) Forecast(deck Deck, now time.Time, days int) []int {
+	forecast := make([]int, days)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for _, question := range deck {
+		day := 0
+		if card, ok := c[question.Text]; ok && card.Due.After(now) {
+			day = int(card.Due.Sub(today) / (24 * time.Hour))
+		}
+		if day < days {
+			forecast[day]++
+		}
+	}
+	return forecast
+}
+
+// StudyStore keeps the cards of every deck studied in a BoltDB, with a
+// bucket per deck
+type StudyStore struct {
+	db *bolt.DB
+}
+
+// NewStudyStore returns a StudyStore stored in db. Closing db is up to the
+// caller
+func NewStudyStore(db *bolt.DB) *StudyStore {
+	return &StudyStore{db: db}
+}
+
+// Cards returns the cards of deck, empty if it was never studied
+func (s *StudyStore) Cards(deck string) (Cards, error) {
+	cards := make(Cards)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket(studyBucket)
+		if root == nil {
+			return nil
+		}
+		b := root.Bucket([]byte(deck))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(text, value []byte) error {
+			var card Card
+			err := json.Unmarshal(value, &card)
+			if err != nil {
+				return err
+			}
+			cards[string(text)] = card
+			return nil
+		})
+	})
+	return cards, err
+}
+
+// Save stores cards as the cards of deck, replacing the ones it has
+func (s *StudyStore) Save(deck string, cards Cards) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		root, err := tx.CreateBucketIfNotExists(studyBucket)
+		if err != nil {
+			return err
+		}
+		b, err := root.CreateBucketIfNotExists([]byte(deck))
+		if err != nil {
+			return err
+		}
+		for text, card := range cards {
+			value, err := json.Marshal(card)
+			if err != nil {
+				return err
+			}
+			err = b.Put([]byte(text), value)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// study plays the questions of deck due at now like a game, and reviews
+// the card of every question answered. Questions that timed out count as
+// missed, and the ones left when the game ended aren't reviewed. Only the
+// first attempt at a question is reviewed, so that a practice retry
+// doesn't promote the card it just missed
+func study(deck Deck, cards Cards, config Config, now time.Time, input io.Reader, sleepy sleeper, output printer) (Result, error) {
+	due := cards.Due(deck, now)
+	if len(due) == 0 {
+		messages := config.messages()
+		output.Println(messages.fill(messages.NothingDue, MessageData{}))
+		return Result{Ending: Completed}, nil
+	}
+	result, err := playGame(due, config, input, sleepy, output)
+	if err != nil {
+		return result, err
+	}
+	for _, answer := range result.Answers {
+		if answer.Attempt > 1 {
+			continue
+		}
+		text := answer.Question.Text
+		cards[text] = cards[text].Review(answer.Correct, now)
+	}
+	return result, nil
+}
+
+// Study asks the questions of source that are due for review, with the
+// options in config, and keeps track of how every question went in store,
+// under the name deck. Questions are spaced out with Leitner boxes: the
+// better a question is known, the longer it waits to be asked again
+func Study(source QuestionSource, deck string, store *StudyStore, config Config) (Result, error) {
+	questions, err := source.Questions()
+	if err != nil {
+		return Result{}, err
+	}
+	cards, err := store.Cards(deck)
+	if err != nil {
+		return Result{}, err
+	}
+	result, err := study(questions, cards, config, time.Now(), os.Stdin, &realSleeper{}, &realPrinter{})
+	if err != nil {
+		return result, err
+	}
+	return result, store.Save(deck, cards)
+}
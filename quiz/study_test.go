@@ -0,0 +1,137 @@
+package quiz
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+func TestStudy(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	t.Run("Correct answers should move a card up a box, and wrong ones back to the first", func(t *testing.T) {
+		card := Card{}.Review(true, now)
+		if card.Box != 1 || !card.Due.Equal(now.Add(day)) {
+			t.Fatalf("Expected box 1 due in a day, got %+v", card)
+		}
+		card = card.Review(true, now).Review(true, now)
+		if card.Box != 3 || !card.Due.Equal(now.Add(7*day)) {
+			t.Fatalf("Expected box 3 due in a week, got %+v", card)
+		}
+		card = card.Review(false, now)
+		if card.Box != 0 || !card.Due.Equal(now) || card.Reviews != 4 || card.Lapses != 1 {
+			t.Fatalf("Expected the first box due now, got %+v", card)
+		}
+	})
+
+	deck := Deck{
+		{Text: "1+1", Answer: "2"},
+		{Text: "2+2", Answer: "4"},
+		{Text: "3+3", Answer: "6"},
+		{Text: "4+4", Answer: "8"},
+	}
+	cards := Cards{
+		"1+1": {Box: 2, Due: now.Add(3 * day)},
+		"3+3": {Box: 1, Due: now.Add(-time.Hour)},
+		"4+4": {Box: 1, Due: now.Add(-day)},
+	}
+
+	t.Run("Due questions should be the most overdue first, then the new ones", func(t *testing.T) {
+		due := cards.Due(deck, now)
+		texts := make([]string, 0, len(due))
+		for _, question := range due {
+			texts = append(texts, question.Text)
+		}
+		expected := []string{"4+4", "3+3", "2+2"}
+		if !reflect.DeepEqual(texts, expected) {
+			t.Fatalf("Expected %v to be due, got %v", expected, texts)
+		}
+	})
+
+	t.Run("The forecast should count the questions due every day", func(t *testing.T) {
+		forecast := cards.Forecast(deck, now, 4)
+		expected := []int{3, 0, 0, 1}
+		if !reflect.DeepEqual(forecast, expected) {
+			t.Fatalf("Expected forecast %v, got %v", expected, forecast)
+		}
+	})
+
+	t.Run("Studying should ask the due questions and review their cards", func(t *testing.T) {
+		studied := Cards{}
+		for text, card := range cards {
+			studied[text] = card
+		}
+		result, err := study(deck, studied, Config{Timer: 30}, now, strings.NewReader("\n8\n5\n4\n"), &expiringSleeper{}, &spyPrinter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Score != 2 || result.MaxScore != 3 {
+			t.Fatalf("Expected 2 out of 3, got %d out of %d", result.Score, result.MaxScore)
+		}
+		if studied["4+4"].Box != 2 || studied["3+3"].Box != 0 || studied["2+2"].Box != 1 {
+			t.Fatalf("Expected the cards to be reviewed, got %+v", studied)
+		}
+		if !reflect.DeepEqual(studied["1+1"], cards["1+1"]) {
+			t.Fatalf("Expected the card that wasn't due to be left alone, got %+v", studied["1+1"])
+		}
+	})
+
+	t.Run("A practice retry should not review the card again", func(t *testing.T) {
+		studied := Cards{}
+		for text, card := range cards {
+			studied[text] = card
+		}
+		config := Config{Timer: 30, Practice: true, Retries: 1}
+		result, err := study(deck, studied, config, now, strings.NewReader("\n9\n6\n4\n8\n"), &expiringSleeper{}, &spyPrinter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Answers) != 4 {
+			t.Fatalf("Expected the missed question to be asked again, got %+v", result.Answers)
+		}
+		if card := studied["4+4"]; card.Box != 0 || card.Reviews != 1 || card.Lapses != 1 {
+			t.Fatalf("Expected the missed card to lapse once, got %+v", card)
+		}
+	})
+
+	t.Run("Nothing should be asked when nothing is due", func(t *testing.T) {
+		printingSpy := &recordingPrinter{}
+		result, _ := study(deck[:1], cards, Config{Timer: 30}, now, strings.NewReader("\n"), &expiringSleeper{}, printingSpy)
+		if len(result.Answers) != 0 || !reflect.DeepEqual(printingSpy.lines, []string{English.NothingDue}) {
+			t.Fatalf("Expected nothing to be asked, got %v", printingSpy.lines)
+		}
+	})
+}
+
+func TestStudyStore(t *testing.T) {
+	db, err := bolt.Open(filepath.Join(t.TempDir(), "study.db"), 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	store := NewStudyStore(db)
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Cards should be kept per deck", func(t *testing.T) {
+		cards := Cards{"1+1": {Box: 2, Due: now, Reviews: 3}}
+		if err := store.Save("maths", cards); err != nil {
+			t.Fatal(err)
+		}
+		got, err := store.Cards("maths")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, cards) {
+			t.Fatalf("Expected cards %v, got %v", cards, got)
+		}
+		other, _ := store.Cards("capitals")
+		if len(other) != 0 {
+			t.Fatalf("Expected no cards for another deck, got %v", other)
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package quiz
+
+var homeTempl = `
+<!DOCTYPE html>
+<html lang="en">
+	<head>
+		<meta charset="UTF-8">
+		<meta name="viewport" content="width=device-width, initial-scale=1.0">
+		<title>Quiz</title>
+	</head>
+	<body>
+		<h1>Quiz</h1>
+		<p>{{.Greeting}}</p>
+		<p>{{.Questions}}</p>
+		<form method="post" action="/start">
+			<button type="submit">{{.Messages.StartButton}}</button>
+		</form>
+	</body>
+</html>
+`
+
+var questionTempl = `
+<!DOCTYPE html>
+<html lang="en">
+	<head>
+		<meta charset="UTF-8">
+		<meta name="viewport" content="width=device-width, initial-scale=1.0">
+		<title>{{.Progress}}</title>
+	</head>
+	<body>
+		{{range .Missed}}<p>{{.}}</p>{{end}}
+		<p>{{.Progress}}</p>
+		{{if .Remaining}}<p>{{.Remaining}}</p>{{end}}
+		{{if .Limit}}<p>{{.Limit}}</p>{{end}}
+		<h1>{{.Question.Text}}</h1>
+		<form method="post" action="/answer">
+			<input type="hidden" name="number" value="{{.Number}}">
+			{{if .Options}}
+			<ul>
+				{{range .Options}}
+				<li><label><input type="radio" name="answer" value="{{.Letter}}"> {{.Label}} {{.Text}}</label></li>
+				{{end}}
+			</ul>
+			{{else}}
+			<input type="text" name="answer" autofocus autocomplete="off">
+			{{end}}
+			<button type="submit">{{.Messages.AnswerButton}}</button>
+			<button type="submit" name="quit" value="{{.Messages.Quit}}">{{.Messages.QuitButton}}</button>
+		</form>
+	</body>
+</html>
+`
+
+var resultTempl = `
+<!DOCTYPE html>
+<html lang="en">
+	<head>
+		<meta charset="UTF-8">
+		<meta name="viewport" content="width=device-width, initial-scale=1.0">
+		<title>Quiz</title>
+	</head>
+	<body>
+		{{range .Missed}}<p>{{.}}</p>{{end}}
+		<h1>{{.Message}}</h1>
+		<ul>
+			{{range .Result.Answers}}
+			<li>{{.Question.Text}}: {{if .TimedOut}}{{$.Messages.OutOfTime}}{{else if .Choice}}{{.Choice}}{{else}}{{.Input}}{{end}} {{if .Correct}}&#10004;{{else}}&#10008;{{end}}</li>
+			{{end}}
+		</ul>
+		<form method="post" action="/start">
+			<button type="submit">{{.Messages.PlayAgain}}</button>
+		</form>
+	</body>
+</html>
+`